@@ -1,29 +1,47 @@
-package main
+package cache
 
-type ARC struct {
+type ARC[K comparable, V any] struct {
 	num_pages int // Total Number of pages in the cache
 	pages_used int
 	num_bytes int // Total Number of bytes in the cache
 	bytes_per_page int // Allowed bytes in each page of memory (floor of bytes/pages)
 	p int // Adaptive parameter
+	byte_mode bool // Whether sizes, including p, are counted in bytes instead of pages
+	size SizeFunc[K, V] // Measures the number of bytes of a binding
 
-	t1 *LRU // T1 = LRU for recently accessed data
-	b1 *LRU // B1 = LRU for data evicted from T1
+	t1 *LRU[K, V] // T1 = LRU for recently accessed data
+	b1 ghostList[K] // B1 = LRU for keys evicted from T1, with their sizes
 
-	t2 *LRU // T2 = LRU for frequently accessed data
-	b2 *LRU // B2 = LRU for data evicted from T2
+	t2 *LRU[K, V] // T2 = LRU for frequently accessed data
+	b2 ghostList[K] // B2 = LRU for keys evicted from T2, with their sizes
+	fingerprint_bits int // Bits of the ghosts' key fingerprints, 0 for exact keys
 
 	hits int // Number of hits on the cache
 	misses int // Number of misses on the cache
+	b1_hits int // Number of requests for keys found in B1
+	b2_hits int // Number of requests for keys found in B2
+	false_positives float64 // Expected ghost hits on fingerprints of other keys
+
+	t1_evictions int // Number of bindings evicted from T1
+	t2_evictions int // Number of bindings evicted from T2
+	promotions int // Number of bindings moved from T1 to T2
+	rejected int // Number of bindings too large to be set
+	min_p int // Smallest p so far
+	max_p int // Largest p so far
+	p_sum int // Sum of p after every request, for its mean
+	sample_every int // Requests between samples of p, 0 for none
+	p_series []int // Samples of p
 }
 
-func NewARC(limit int, pages int) *ARC {
-	t1 := NewLru(limit, pages)
-	t2 := NewLru(limit, pages)
-	b1 := NewLru(limit, pages)
-	b2 := NewLru(limit, pages)
+// NewARC returns an ARC holding pages bindings, each of which may use up to
+// limit/pages bytes as measured by DefaultSize.
+func NewARC[K comparable, V any](limit int, pages int) *ARC[K, V] {
+	t1 := NewLru[K, V](limit, pages)
+	t2 := NewLru[K, V](limit, pages)
+	b1 := keyGhosts[K]{newHistory[K, int](pages)}
+	b2 := keyGhosts[K]{newHistory[K, int](pages)}
 
-	return &ARC{
+	return &ARC[K, V]{
 		num_pages: pages,
 		pages_used: 0,
 		num_bytes: limit,
@@ -33,96 +51,121 @@ func NewARC(limit int, pages int) *ARC {
 		b1: b1,
 		t2: t2,
 		b2: b2,
+		size: DefaultSize[K, V],
 		hits: 0,
 		misses: 0,
 	}
 }
 
+// NewByteARC returns an ARC that admits bindings of any size up to limit
+// bytes as measured by size, evicting by total bytes rather than by page.
+// The target size p of T1 adapts in bytes: a ghost hit moves it by the size
+// of the requested binding, scaled by the ratio of the ghost lists' bytes.
+// Bindings count as at least one byte, so that ones of size 0 are evicted.
+// MaxPages and RemainingPages count bytes for such an ARC.
+func NewByteARC[K comparable, V any](limit int, size SizeFunc[K, V]) *ARC[K, V] {
+	return &ARC[K, V]{
+		num_pages: limit,
+		num_bytes: limit,
+		bytes_per_page: 1,
+		byte_mode: true,
+		size: atLeastOneByte(size),
+		t1: NewByteLru[K, V](limit, size),
+		b1: keyGhosts[K]{NewByteLru[K, int](limit, ghostSize[K])},
+		t2: NewByteLru[K, V](limit, size),
+		b2: keyGhosts[K]{NewByteLru[K, int](2*limit, ghostSize[K])},
+	}
+}
+
 // MaxPages returns the number of pages that are available in the cache total
 // regardless of how many have been used or are empty.
-func (arc *ARC) MaxPages() int {
+func (arc *ARC[K, V]) MaxPages() int {
 	return arc.num_pages
 }
 
 // Remaining pages tells how many pages are remaining in the cache that are
 // still empty.
-func (arc *ARC) RemainingPages() int {
+func (arc *ARC[K, V]) RemainingPages() int {
+	if arc.byte_mode {
+		return arc.num_bytes - arc.Bytes()
+	}
 	return arc.num_pages - arc.pages_used
 }
 
+// Bytes returns the total size of the bindings in t1 and t2.
+func (arc *ARC[K, V]) Bytes() int {
+	return arc.t1.Bytes() + arc.t2.Bytes()
+}
+
 // Get returns the value of the key if it is in t1 or t2.
 // It also returns a boolean if the key is in the cache or not
-func (arc *ARC) Get(key string) (value []byte, ok bool) {
+func (arc *ARC[K, V]) Get(key K) (value V, ok bool) {
+	defer arc.observe()
 	if arc.t1.Contains(key){
 		v, ok := arc.t1.Remove(key)
 		if !ok {
-			return nil, false
+			return value, false
 		}
 		arc.t2.Set(key, v)
+		arc.promotions += 1
 		arc.hits += 1
 		return v, true
 	}
 	if arc.t2.Contains(key){
 		v, ok := arc.t2.Get(key)
 		if !ok {
-			return nil, false
+			return value, false
 		}
 		arc.hits += 1
 		return v, true
 	}
 	arc.misses += 1
-	return nil, false
+	return value, false
 }
 
-func (arc *ARC) Set(key string, value []byte) {
-	if len(key) + len(value) > arc.bytes_per_page{
-		return
+// Set adds the key to the cache following the four cases of the ARC paper.
+// It returns false if the binding is too large to fit in a page.
+func (arc *ARC[K, V]) Set(key K, value V) bool {
+	if arc.byte_mode {
+		return arc.setBytes(key, value)
+	}
+	if arc.size(key, value) > arc.bytes_per_page{
+		arc.rejected += 1
+		return false
 	}
 	// CASE 1
 	if arc.t1.Contains(key){
 		arc.t1.Remove(key)
 		arc.t2.Set(key, value)
-		return
+		arc.promotions += 1
+		return true
 	}
 	if arc.t2.Contains(key){
 		arc.t2.Set(key, value)
-		return
+		return true
 	}
 
 	// CASE 2
+	arc.probeGhosts()
 	if arc.b1.Contains(key){
-		delta := 1
-		if arc.b1.Len() < arc.b2.Len(){
-			delta = arc.b2.Len() / arc.b1.Len()
-		}
-		if arc.p + delta > arc.num_pages{
-			arc.p = arc.num_pages
-		} else{
-			arc.p += delta
-		}
+		arc.b1_hits += 1
+		arc.adapt(growTarget(arc.p, arc.num_pages, 1, arc.b1.Len(), arc.b2.Len()))
 		arc.Replace(key)
 		arc.b1.Remove(key)
 		arc.t2.Set(key, value)
 		arc.pages_used += 1
-		return
+		return true
 	}
 
 	// CASE 3
 	if arc.b2.Contains(key){
-		delta := 1
-		if arc.b2.Len() < arc.b1.Len(){
-			delta = arc.b1.Len() / arc.b2.Len()
-		}
-		if arc.p - delta < 0{
-			arc.p = 0
-		} else{
-			arc.p -= delta
-		}
+		arc.b2_hits += 1
+		arc.adapt(shrinkTarget(arc.p, 1, arc.b1.Len(), arc.b2.Len()))
 		arc.Replace(key)
 		arc.b2.Remove(key)
 		arc.t2.Set(key, value)
 		arc.pages_used += 1
-		return
+		return true
 	}
 
 	// CASE 4
@@ -133,6 +176,7 @@ func (arc *ARC) Set(key string, value []byte) {
 			arc.Replace(key)
 		} else{
 			arc.t1.RemoveLRU()
+			arc.t1_evictions += 1
 			arc.pages_used -= 1
 		}
 	} else if arc.t1.Len() + arc.b1.Len() < arc.num_pages{
@@ -145,27 +189,140 @@ func (arc *ARC) Set(key string, value []byte) {
 	}
 	arc.t1.Set(key, value)
 	arc.pages_used += 1
+	return true
+}
+
+// growTarget returns the target size p of T1 after a hit in B1, as in Case 2
+// of the ARC paper: p grows by unit, or by unit times |B2|/|B1| if B2 is the
+// larger ghost list, up to the cache size c. B1 may be empty in bytes while
+// holding bindings of size 0, so it counts as at least 1.
+func growTarget(p int, c int, unit int, b1 int, b2 int) int {
+	delta := unit
+	if b1 < b2{
+		delta = unit * b2 / max(b1, 1)
+	}
+	return min(p + delta, c)
+}
+
+// shrinkTarget returns the target size p of T1 after a hit in B2, as in
+// Case 3 of the ARC paper: the mirror image of growTarget.
+func shrinkTarget(p int, unit int, b1 int, b2 int) int {
+	delta := unit
+	if b2 < b1{
+		delta = unit * b1 / max(b2, 1)
+	}
+	return max(p - delta, 0)
 }
 
 // Replace function from ARC research paper
-func (arc *ARC) Replace(key string){
+func (arc *ARC[K, V]) Replace(key K){
 	if arc.t1.Len() > 0 && (arc.t1.Len() > arc.p || (arc.b2.Contains(key) && arc.t1.Len() == arc.p)){
-		rkey, rval := arc.t1.RemoveLRU()
-		arc.b1.Set(rkey, rval)
+		arc.demote(arc.t1, arc.b1)
 	} else{
-		rkey, rval := arc.t2.RemoveLRU()
-		arc.b2.Set(rkey, rval)
+		arc.demote(arc.t2, arc.b2)
 	}
 	arc.pages_used -= 1
 }
 
+// demote evicts the LRU binding of list, keeping only its key and size in
+// the ghost list, so ghosts do not hold on to evicted values.
+func (arc *ARC[K, V]) demote(list *LRU[K, V], ghost ghostList[K]){
+	key, value := list.RemoveLRU()
+	ghost.Set(key, arc.size(key, value))
+	if list == arc.t1{
+		arc.t1_evictions += 1
+	} else{
+		arc.t2_evictions += 1
+	}
+}
+
+// ghostSize measures a ghost by the size its binding had.
+func ghostSize[K comparable](key K, size int) int {
+	return size
+}
+
+// setBytes is Set for an ARC that counts bytes. It follows the same four
+// cases, but evicts as many bindings as needed to make room for the new one.
+func (arc *ARC[K, V]) setBytes(key K, value V) bool {
+	size := arc.size(key, value)
+	if size > arc.num_bytes{
+		arc.rejected += 1
+		return false
+	}
+	// CASE 1
+	if arc.t1.Contains(key) || arc.t2.Contains(key){
+		if arc.t1.Contains(key){
+			arc.promotions += 1
+		}
+		arc.t1.Remove(key)
+		arc.t2.Remove(key)
+		arc.replaceBytes(key, size)
+		arc.t2.Set(key, value)
+		return true
+	}
+
+	// CASE 2
+	arc.probeGhosts()
+	if arc.b1.Contains(key){
+		arc.b1_hits += 1
+		arc.adapt(growTarget(arc.p, arc.num_bytes, size, arc.b1.Bytes(), arc.b2.Bytes()))
+		arc.replaceBytes(key, size)
+		arc.b1.Remove(key)
+		arc.t2.Set(key, value)
+		return true
+	}
+
+	// CASE 3
+	if arc.b2.Contains(key){
+		arc.b2_hits += 1
+		arc.adapt(shrinkTarget(arc.p, size, arc.b1.Bytes(), arc.b2.Bytes()))
+		arc.replaceBytes(key, size)
+		arc.b2.Remove(key)
+		arc.t2.Set(key, value)
+		return true
+	}
+
+	// CASE 4
+	// CASE 4.A: keep |T1| + |B1| within the cache, preferring to drop ghosts
+	for arc.t1.Bytes() + arc.b1.Bytes() + size > arc.num_bytes && arc.b1.Len() > 0{
+		arc.b1.RemoveLRU()
+	}
+	for arc.t1.Bytes() + size > arc.num_bytes{
+		arc.t1.RemoveLRU()
+		arc.t1_evictions += 1
+	}
+	// CASE 4.B: keep the whole directory within twice the cache
+	for arc.directoryBytes() + size > 2 * arc.num_bytes && arc.b2.Len() > 0{
+		arc.b2.RemoveLRU()
+	}
+	arc.replaceBytes(key, size)
+	arc.t1.Set(key, value)
+	return true
+}
+
+// replaceBytes runs Replace until there is room for size more bytes.
+func (arc *ARC[K, V]) replaceBytes(key K, size int){
+	for arc.Bytes() + size > arc.num_bytes{
+		if arc.t1.Len() > 0 && (arc.t1.Bytes() > arc.p || (arc.b2.Contains(key) && arc.t1.Bytes() == arc.p) || arc.t2.Len() == 0){
+			arc.demote(arc.t1, arc.b1)
+		} else{
+			arc.demote(arc.t2, arc.b2)
+		}
+	}
+}
+
+// directoryBytes returns the bytes tracked by all four lists.
+func (arc *ARC[K, V]) directoryBytes() int {
+	return arc.t1.Bytes() + arc.t2.Bytes() + arc.b1.Bytes() + arc.b2.Bytes()
+}
+
 // Len returns the number of pages that have been used in the cache
-func (arc *ARC) Len() int {
+func (arc *ARC[K, V]) Len() int {
 	return arc.t1.Len() + arc.t2.Len()
 }
 
 // Stats returns statistics about how many search hits and misses have occurred.
-func (arc *ARC) Stats() *Stats {
+func (arc *ARC[K, V]) Stats() *Stats {
 	return &Stats{
 		Hits: arc.hits,
 		Misses: arc.misses,
@@ -6,10 +6,11 @@
  *    An unit testing suite for arc.go.
  ******************************************************************************/
 
-package test
+package cache
 
 import (
 	"fmt"
+	"runtime"
 	"testing"
 )
 
@@ -17,8 +18,8 @@ import (
 /*                                Constants                                   */
 /******************************************************************************/
 
-const cap = 128
-const p = 8
+const limit = 128
+const pages = 8
 
 /******************************************************************************/
 /*                                  Tests                                     */
@@ -27,7 +28,7 @@ const p = 8
 // Checks properties of an empty cache
 func TestARCEmpty(t *testing.T) {
 	capacity := 64
-	arc := NewARC(capacity, p)
+	arc := NewARC[string, []byte](capacity, pages)
 
 	// Empty
 	if arc.Len() != 0{
@@ -36,7 +37,7 @@ func TestARCEmpty(t *testing.T) {
 	}
 
 	capacity = 256
-	arc = NewARC(capacity, p)
+	arc = NewARC[string, []byte](capacity, pages)
 
 	// Empty
 	if arc.Len() != 0{
@@ -46,7 +47,7 @@ func TestARCEmpty(t *testing.T) {
 
 	// Empty
 	capacity = 1024
-	arc = NewARC(capacity, p)
+	arc = NewARC[string, []byte](capacity, pages)
 
 	if arc.Len() != 0{
 		t.Errorf("Failed to create an empty cache. Length is: %d", arc.Len())
@@ -56,7 +57,7 @@ func TestARCEmpty(t *testing.T) {
 
 // Checks proper get on an empty cache
 func TestARCEmptyGet(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Empty
 	if arc.Len() != 0{
@@ -75,7 +76,7 @@ func TestARCEmptyGet(t *testing.T) {
 
 // Checks that a single binding can be added to the cache
 func TestARCSingleBinding(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add a key
 	key := "key"
@@ -102,7 +103,7 @@ func TestARCSingleBinding(t *testing.T) {
 
 // Checks that all page bindings can be filled and used
 func TestARCFillBindings(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Fill keys 1-8
 	for i := 1; i <= 8; i++ {
@@ -127,7 +128,7 @@ func TestARCFillBindings(t *testing.T) {
 
 // Checks that a binding that is too large for the given page size cannot be added
 func TestARCBindingTooLarge(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Too large of a page binding
 	key := "keytoobig"
@@ -149,7 +150,7 @@ func TestARCBindingTooLarge(t *testing.T) {
 // Checks that overfilling a cache does not add more space and that
 // hits and misses are proper.
 func TestARCOverFill(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add keys 1-9 with 8 pages to test a removal of the first key
 	for i := 1; i <= 9; i++ {
@@ -195,7 +196,7 @@ func TestARCOverFill(t *testing.T) {
 
 // Checks that keys in T1 will be moved to T2 on reaccess
 func TestARCCaseOne(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	addKeys(arc, 1, 8)
 
@@ -222,7 +223,7 @@ func TestARCCaseOne(t *testing.T) {
 
 // Checks that ghost list B1 gets populated on deletion
 func TestARCPopulateB1(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add 1-8
 	addKeys(arc, 1, 8)
@@ -246,7 +247,7 @@ func TestARCPopulateB1(t *testing.T) {
 // Checks that Case 2 (values in B1) move to the correct spot
 // And that accessing in the middle is correct
 func TestARCCase2(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add 1-8
 	addKeys(arc, 1, 8)
@@ -305,7 +306,7 @@ func TestARCCase2(t *testing.T) {
 
 // Checking updates in Case 3 of ARC paper
 func TestARCCaseThree(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 	
 	// Add 1-8 to T1
 	addKeys(arc, 1, 8)
@@ -350,7 +351,7 @@ func TestARCCaseThree(t *testing.T) {
 
 // Checks updating in Case 4A of the ARC paper
 func TestARCCaseFourA(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 	
 	// 8 Items in T1
 	addKeys(arc, 1, 8)
@@ -367,7 +368,7 @@ func TestARCCaseFourA(t *testing.T) {
 
 // Checks updating in Case 4B of the ARC paper
 func TestARCCaseFourB(t *testing.T) {
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 	
 	// 8 Items in T1
 	addKeys(arc, 1, 8)
@@ -393,7 +394,7 @@ func TestARCCaseFourB(t *testing.T) {
 
 // Checks a large number of operations when ghost lists are not used
 func TestARCLargeNumOpsNoGhost(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	addKeys(arc, 1, 200)
 
@@ -420,7 +421,7 @@ func TestARCLargeNumOpsNoGhost(t *testing.T){
 
 // Checks that a short workload will return the correct length of caches
 func TestARCSmallNumOpsWithGhost(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add 1-8 to T1
 	addKeys(arc, 1, 8)
@@ -448,7 +449,7 @@ func TestARCSmallNumOpsWithGhost(t *testing.T){
 
 // Tests that a key is updated and not readded
 func TestARCKeyUpdate(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Add key 1
 	addKeys(arc, 1, 1)
@@ -489,7 +490,7 @@ func TestARCKeyUpdate(t *testing.T){
 
 // Tests that the max num of pages is correct
 func TestARCMaxPages(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	max := arc.MaxPages()
 
@@ -502,7 +503,7 @@ func TestARCMaxPages(t *testing.T){
 
 // Tests that remaining pages updates properly
 func TestARCRemainingPages(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	max := arc.MaxPages()
 
@@ -527,7 +528,7 @@ func TestARCRemainingPages(t *testing.T){
 }
 
 func SimpleStats(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	addKeys(arc, 1, 8)
 
@@ -547,7 +548,7 @@ func SimpleStats(t *testing.T){
 }
 
 func LongerStats(t *testing.T){
-	arc := NewARC(cap, p)
+	arc := NewARC[string, []byte](limit, pages)
 
 	// Case From 4B
 	addKeys(arc, 1, 8)
@@ -583,4 +584,188 @@ func LongerStats(t *testing.T){
 		t.Errorf("Misses are %d. Should be: %d", arc.misses, 9)
 		t.FailNow()
 	}
-}
\ No newline at end of file
+}
+// Checks that ARC works with keys and values that are not strings or bytes
+func TestARCGenericTypes(t *testing.T) {
+	type entry struct {
+		id   int
+		name string
+	}
+	arc := NewARC[int, entry](limit, pages)
+
+	for i := 1; i <= 8; i++ {
+		arc.Set(i, entry{i, fmt.Sprintf("key%d", i)})
+	}
+	// Move 1-4 to T2
+	for i := 1; i <= 4; i++ {
+		arc.Get(i)
+	}
+	arc.Set(9, entry{9, "key9"})
+
+	val, ok := arc.Get(9)
+	if !ok || val.id != 9 || val.name != "key9" {
+		t.Errorf("Wrong value: %v for binding with key: %d", val, 9)
+		t.FailNow()
+	}
+	if _, ok := arc.Get(5); ok {
+		t.Errorf("Failed to cache miss on a removed key. Key is: %d", 5)
+		t.FailNow()
+	}
+
+	var _ Cache[int, entry] = arc
+}
+
+// valueSize measures a binding by its value, for tests in byte mode
+func valueSize(key string, value int) int {
+	return value
+}
+
+// Checks that a byte-mode ARC evicts by total bytes and rejects oversized bindings
+func TestARCByteMode(t *testing.T) {
+	arc := NewByteARC[string, int](100, valueSize)
+
+	arc.Set("a", 60)
+	arc.Set("b", 30)
+	if arc.RemainingPages() != 10 {
+		t.Errorf("Wrong value: %d for remaining bytes. Value should be: %d", arc.RemainingPages(), 10)
+		t.FailNow()
+	}
+
+	// Needs 20 bytes but T1 alone would overflow L1, so a, the LRU of T1, is
+	// dropped without a ghost as when |T1| = c in the paper
+	arc.Set("c", 20)
+	if _, ok := arc.Get("a"); ok {
+		t.Errorf("Failed to cache miss on a removed key. Key is: %s", "a")
+		t.FailNow()
+	}
+	if arc.Bytes() != 50 || arc.b1.Bytes() != 0 {
+		t.Errorf("Wrong byte counts. Cache is: %d, B1 is: %d when they should be %d and %d", arc.Bytes(), arc.b1.Bytes(), 50, 0)
+		t.FailNow()
+	}
+
+	// Too large to ever fit
+	if arc.Set("huge", 101) {
+		t.Errorf("Failed to reject a binding larger than the cache. Key is: %s", "huge")
+		t.FailNow()
+	}
+}
+
+// Checks that p adapts in bytes on ghost hits
+func TestARCByteModeAdaptation(t *testing.T) {
+	arc := NewByteARC[string, int](100, valueSize)
+
+	arc.Set("a", 40)
+	arc.Get("a") // a moves to T2
+	arc.Set("b", 40)
+	arc.Set("c", 40) // b moves to B1
+
+	checkLengths(arc, t, 1, 1, 1, 0)
+
+	// Ghost hit in B1 grows p by the size of b
+	arc.Set("b", 40)
+	if arc.p != 40 {
+		t.Errorf("Failed to update parameter in bytes. P is: %d when it should be %d", arc.p, 40)
+		t.FailNow()
+	}
+	if !arc.t2.Contains("b") {
+		t.Errorf("Failed to move a ghost hit into T2. Key is: %s", "b")
+		t.FailNow()
+	}
+	if arc.Bytes() > 100 {
+		t.Errorf("Overfilled the cache. Bytes are: %d when max is %d", arc.Bytes(), 100)
+		t.FailNow()
+	}
+}
+
+// Checks ARC's hits on the sample traces, which keeping only keys in the
+// ghost lists must not change
+func TestARCTraceHits(t *testing.T) {
+	for _, tc := range []struct {
+		trace string
+		pages int
+		hits  int
+	}{
+		{"trace1.txt", 10, 26822},
+		{"trace1.txt", 100, 58417},
+		{"trace1.txt", 1000, 96710},
+		{"trace2.txt", 10, 2671},
+		{"trace2.txt", 100, 5683},
+	} {
+		arc := NewARC[string, int](10*tc.pages, tc.pages)
+		for _, key := range readKeys(t, tc.trace) {
+			if _, ok := arc.Get(key); !ok {
+				arc.Set(key, 0)
+			}
+		}
+		if arc.hits != tc.hits {
+			t.Errorf("Changed the hits on %s with %d pages. Hits are: %d when they should be %d", tc.trace, tc.pages, arc.hits, tc.hits)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks that ghost hits on bindings of size 0 do not divide by zero
+func TestARCByteModeZeroSize(t *testing.T) {
+	arc := NewByteARC[string, int](10, valueSize)
+
+	for _, req := range []struct {
+		key  string
+		size int
+	}{{"z", 0}, {"w", 1}, {"x", 5}, {"x", 5}, {"y", 9}, {"w", 1}, {"z", 0}} {
+		if _, ok := arc.Get(req.key); !ok {
+			arc.Set(req.key, req.size)
+		}
+	}
+	if arc.Bytes() > 10 {
+		t.Errorf("Overfilled the cache. Bytes are: %d when max is %d", arc.Bytes(), 10)
+		t.FailNow()
+	}
+}
+
+// Checks that bindings of size 0 are evicted from every list
+func TestARCByteModeZeroSizeBound(t *testing.T) {
+	arc := NewByteARC[string, int](10, valueSize)
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		arc.Set(key, 0)
+		if i%3 == 0 {
+			arc.Get(key)
+		}
+	}
+	if arc.Len() > 10 || arc.b1.Len() > 10 || arc.b2.Len() > 20 {
+		t.Errorf("Failed to bound bindings of size 0. T1 %d, T2 %d, B1 %d, B2 %d", arc.t1.Len(), arc.t2.Len(), arc.b1.Len(), arc.b2.Len())
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+// Reports the heap bytes an ARC takes per cached binding of 256 bytes once
+// its ghost lists are full
+func BenchmarkARCMemory(b *testing.B) {
+	const n = 1 << 12
+	var stats runtime.MemStats
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&stats)
+		before := stats.HeapAlloc
+
+		arc := NewARC[int, []byte](256*n, n)
+		for key := 0; key < n; key++ {
+			arc.Set(key, make([]byte, 256))
+			arc.Get(key)
+		}
+		// New keys push the old ones from T2 into B2, then their own into B1
+		for key := n; key < 3*n; key++ {
+			arc.Set(key, make([]byte, 256))
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&stats)
+		b.ReportMetric(float64(stats.HeapAlloc-before)/float64(arc.Len()), "B/entry")
+		runtime.KeepAlive(arc)
+	}
+}
@@ -0,0 +1,86 @@
+package cache
+
+// ARCStats extends Stats with what ARC did to serve the requests: how
+// bindings moved between its lists, and how its target size p of T1 adapted.
+type ARCStats struct {
+	Stats
+	GhostStats
+
+	T1Evictions int // Bindings evicted from T1, into B1 or not
+	T2Evictions int // Bindings evicted from T2 into B2
+	Promotions  int // Bindings moved from T1 to T2 on a second use
+	Rejected    int // Sets of bindings too large for the cache
+
+	P     int     // Current target size of T1
+	MinP  int     // Smallest target size so far
+	MaxP  int     // Largest target size so far
+	MeanP float64 // Target size averaged over the requests
+
+	T1Len int
+	T2Len int
+	B1Len int
+	B2Len int
+
+	PSeries []int // Target size every SampleTarget requests
+}
+
+// SampleTarget makes ARC record its target size p every n requests, for
+// ARCStats.PSeries, or stop recording if n is 0. Requests are counted by Get.
+func (arc *ARC[K, V]) SampleTarget(n int) {
+	arc.sample_every = max(n, 0)
+}
+
+// observe records the target size after a request.
+func (arc *ARC[K, V]) observe() {
+	requests := arc.hits + arc.misses
+	arc.p_sum += arc.p
+	if arc.sample_every > 0 && requests%arc.sample_every == 0 {
+		arc.p_series = append(arc.p_series, arc.p)
+	}
+}
+
+// adapt sets the target size p, keeping track of its range.
+func (arc *ARC[K, V]) adapt(p int) {
+	arc.p = p
+	arc.min_p = min(arc.min_p, p)
+	arc.max_p = max(arc.max_p, p)
+}
+
+// ResetStats forgets the requests made so far in every statistic, keeping
+// the bindings, ghosts and target size p, so the statistics can leave out a
+// warm-up. The range of p starts again from its current value.
+func (arc *ARC[K, V]) ResetStats() {
+	arc.hits, arc.misses = 0, 0
+	arc.b1_hits, arc.b2_hits = 0, 0
+	arc.false_positives = 0
+	arc.t1_evictions, arc.t2_evictions = 0, 0
+	arc.promotions, arc.rejected = 0, 0
+	arc.min_p, arc.max_p = arc.p, arc.p
+	arc.p_sum = 0
+	arc.p_series = nil
+}
+
+// ARCStats returns the extended statistics of the ARC. The series of p is
+// shared with the ARC, which keeps appending to it.
+func (arc *ARC[K, V]) ARCStats() *ARCStats {
+	stats := &ARCStats{
+		Stats:       *arc.Stats(),
+		GhostStats:  *arc.GhostStats(),
+		T1Evictions: arc.t1_evictions,
+		T2Evictions: arc.t2_evictions,
+		Promotions:  arc.promotions,
+		Rejected:    arc.rejected,
+		P:           arc.p,
+		MinP:        arc.min_p,
+		MaxP:        arc.max_p,
+		T1Len:       arc.t1.Len(),
+		T2Len:       arc.t2.Len(),
+		B1Len:       arc.b1.Len(),
+		B2Len:       arc.b2.Len(),
+		PSeries:     arc.p_series,
+	}
+	if requests := arc.hits + arc.misses; requests > 0 {
+		stats.MeanP = float64(arc.p_sum) / float64(requests)
+	}
+	return stats
+}
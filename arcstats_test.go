@@ -0,0 +1,107 @@
+/******************************************************************************
+ * arcstats_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for arcstats.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks the counts of evictions, promotions, ghost hits and rejections
+func TestARCStatsCounts(t *testing.T) {
+	arc := NewARC[string, []byte](limit, pages)
+
+	addCache(arc, 1, 8)
+	arc.Get("key1")     // Promoted to T2
+	addCache(arc, 9, 9) // key2 evicted from T1 into B1
+	addCache(arc, 2, 2) // Ghost hit in B1, evicting key3 from T1 into B1
+	arc.Set("big", make([]byte, limit))
+
+	stats := arc.ARCStats()
+	if stats.Promotions != 1 || stats.T1Evictions != 2 || stats.T2Evictions != 0 {
+		t.Errorf("Miscounted moves. Promotions: %d, T1 evictions: %d, T2 evictions: %d", stats.Promotions, stats.T1Evictions, stats.T2Evictions)
+		t.FailNow()
+	}
+	if stats.B1Hits != 1 || stats.Rejected != 1 || stats.Hits != 1 {
+		t.Errorf("Miscounted hits or rejections. B1 hits: %d, rejected: %d, hits: %d", stats.B1Hits, stats.Rejected, stats.Hits)
+		t.FailNow()
+	}
+	if stats.P != 1 || stats.MaxP != 1 || stats.MinP != 0 {
+		t.Errorf("Wrong range of p. P: %d, min: %d, max: %d", stats.P, stats.MinP, stats.MaxP)
+		t.FailNow()
+	}
+	if stats.T1Len != arc.t1.Len() || stats.T2Len != 2 || stats.B1Len != 1 || stats.B2Len != 0 {
+		t.Errorf("Wrong list sizes. T1: %d, T2: %d, B1: %d, B2: %d", stats.T1Len, stats.T2Len, stats.B1Len, stats.B2Len)
+		t.FailNow()
+	}
+}
+
+// Checks the samples and mean of p over a trace, and that every binding
+// set is either cached or counted as evicted
+func TestARCStatsTrace(t *testing.T) {
+	arc := NewARC[string, int](1000, 100)
+	arc.SampleTarget(1000)
+	keys := readKeys(t, "trace1.txt")
+	for _, key := range keys {
+		if _, ok := arc.Get(key); !ok {
+			arc.Set(key, 0)
+		}
+	}
+
+	stats := arc.ARCStats()
+	if len(stats.PSeries) != len(keys)/1000 {
+		t.Errorf("Wrong number of samples of p. Samples are: %d when they should be %d", len(stats.PSeries), len(keys)/1000)
+		t.FailNow()
+	}
+	for _, p := range stats.PSeries {
+		if p < stats.MinP || p > stats.MaxP {
+			t.Errorf("Sampled p out of its range. P is: %d, range is %d to %d", p, stats.MinP, stats.MaxP)
+			t.FailNow()
+		}
+	}
+	if stats.MeanP < float64(stats.MinP) || stats.MeanP > float64(stats.MaxP) || stats.MaxP > 100 {
+		t.Errorf("Mean of p out of its range. Mean is: %f, range is %d to %d", stats.MeanP, stats.MinP, stats.MaxP)
+		t.FailNow()
+	}
+	if arc.Len() != stats.Misses-stats.Rejected-stats.T1Evictions-stats.T2Evictions {
+		t.Errorf("Lost track of evictions. Length is: %d, misses: %d, rejected: %d, evicted: %d", arc.Len(), stats.Misses, stats.Rejected, stats.T1Evictions+stats.T2Evictions)
+		t.FailNow()
+	}
+}
+
+// Checks that resetting the statistics keeps the bindings and p
+func TestARCResetStats(t *testing.T) {
+	arc := NewARC[string, []byte](limit, pages)
+	arc.SampleTarget(1)
+
+	addCache(arc, 1, 8)
+	arc.Get("key1")
+	addCache(arc, 9, 9)
+	addCache(arc, 2, 2) // Ghost hit in B1, raising p
+	p := arc.ARCStats().P
+	arc.ResetStats()
+	arc.Get("key9")
+
+	stats := arc.ARCStats()
+	if stats.Hits != 1 || stats.Misses != 0 || stats.B1Hits != 0 || stats.T1Evictions != 0 || stats.Promotions != 1 {
+		t.Errorf("Failed to reset counts. Hits: %d, misses: %d, B1 hits: %d, T1 evictions: %d, promotions: %d", stats.Hits, stats.Misses, stats.B1Hits, stats.T1Evictions, stats.Promotions)
+		t.FailNow()
+	}
+	if stats.P != p || stats.MinP != p || stats.MaxP != p || stats.MeanP != float64(p) || len(stats.PSeries) != 1 {
+		t.Errorf("Failed to reset p's statistics. P: %d, min: %d, max: %d, mean: %f, samples: %d", stats.P, stats.MinP, stats.MaxP, stats.MeanP, len(stats.PSeries))
+		t.FailNow()
+	}
+	if stats.T1Len+stats.T2Len != 8 || stats.B1Len != 1 {
+		t.Errorf("Lost bindings or ghosts. T1: %d, T2: %d, B1: %d", stats.T1Len, stats.T2Len, stats.B1Len)
+		t.FailNow()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"math/rand"
+)
+
+// A FIFO evicts the page that was added first, whether or not it has been
+// used since. It is an LRU whose hits do not move pages.
+type FIFO[K comparable, V any] struct {
+	queue *LRU[K, V] // Front is the oldest page
+
+	hits   int
+	misses int
+}
+
+// NewFIFO returns a FIFO holding pages bindings of up to limit/pages bytes.
+func NewFIFO[K comparable, V any](limit int, pages int) *FIFO[K, V] {
+	return &FIFO[K, V]{queue: NewLru[K, V](limit, pages)}
+}
+
+func (fifo *FIFO[K, V]) MaxPages() int {
+	return fifo.queue.MaxPages()
+}
+
+func (fifo *FIFO[K, V]) RemainingPages() int {
+	return fifo.queue.RemainingPages()
+}
+
+// Get returns the value of key, leaving its place in the queue.
+func (fifo *FIFO[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = fifo.queue.Peek(key)
+	if ok {
+		fifo.hits += 1
+	} else {
+		fifo.misses += 1
+	}
+	return value, ok
+}
+
+// Set adds the binding at the back of the queue, evicting the oldest page
+// if the cache is full. Setting a cached key replaces its value where it is.
+func (fifo *FIFO[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > fifo.queue.page_size {
+		return false
+	}
+	if fifo.queue.Contains(key) {
+		fifo.queue.update(key, value)
+		return true
+	}
+	return fifo.queue.Set(key, value)
+}
+
+func (fifo *FIFO[K, V]) Len() int {
+	return fifo.queue.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (fifo *FIFO[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   fifo.hits,
+		Misses: fifo.misses,
+	}
+}
+
+// An MRU evicts the most recently used page, which suits loops over more
+// pages than fit in the cache, where the page just used is the one needed
+// last. It is an LRU that evicts from the other end.
+type MRU[K comparable, V any] struct {
+	recency *LRU[K, V] // Back is the most recently used page
+}
+
+// NewMRU returns an MRU holding pages bindings of up to limit/pages bytes.
+func NewMRU[K comparable, V any](limit int, pages int) *MRU[K, V] {
+	return &MRU[K, V]{recency: NewLru[K, V](limit, pages)}
+}
+
+func (mru *MRU[K, V]) MaxPages() int {
+	return mru.recency.MaxPages()
+}
+
+func (mru *MRU[K, V]) RemainingPages() int {
+	return mru.recency.RemainingPages()
+}
+
+// Get returns the value of key, making it most recently used.
+func (mru *MRU[K, V]) Get(key K) (value V, ok bool) {
+	return mru.recency.Get(key)
+}
+
+// Set adds the binding as most recently used, first evicting the most
+// recently used page if the cache is full. Setting a cached key replaces
+// its value where it is.
+func (mru *MRU[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > mru.recency.page_size {
+		return false
+	}
+	if mru.recency.Contains(key) {
+		mru.recency.update(key, value)
+		return true
+	}
+	if mru.recency.RemainingPages() == 0 {
+		if key, ok := mru.recency.newest(); ok {
+			mru.recency.Remove(key)
+		}
+	}
+	return mru.recency.Set(key, value)
+}
+
+func (mru *MRU[K, V]) Len() int {
+	return mru.recency.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (mru *MRU[K, V]) Stats() *Stats {
+	return mru.recency.Stats()
+}
+
+// A Random evicts a page chosen uniformly at random.
+type Random[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	rand           *rand.Rand
+
+	entries  map[K]int // Position of each key in resident
+	resident []randomEntry[K, V]
+
+	hits   int
+	misses int
+}
+
+type randomEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewRandom returns a Random holding pages bindings of up to limit/pages
+// bytes, choosing pages to evict with the given seed.
+func NewRandom[K comparable, V any](limit int, pages int, seed int64) *Random[K, V] {
+	return &Random[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		rand:           rand.New(rand.NewSource(seed)),
+		entries:        make(map[K]int),
+	}
+}
+
+func (r *Random[K, V]) MaxPages() int {
+	return r.num_pages
+}
+
+func (r *Random[K, V]) RemainingPages() int {
+	return r.num_pages - r.Len()
+}
+
+// Get returns the value of key.
+func (r *Random[K, V]) Get(key K) (value V, ok bool) {
+	i, ok := r.entries[key]
+	if !ok {
+		r.misses += 1
+		return value, false
+	}
+	r.hits += 1
+	return r.resident[i].value, true
+}
+
+// Set adds the binding, evicting a random page if the cache is full.
+// Setting a cached key replaces its value.
+func (r *Random[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > r.bytes_per_page {
+		return false
+	}
+	if i, ok := r.entries[key]; ok {
+		r.resident[i].value = value
+		return true
+	}
+	if r.Len() == r.num_pages {
+		r.evict(r.rand.Intn(len(r.resident)))
+	}
+	r.entries[key] = len(r.resident)
+	r.resident = append(r.resident, randomEntry[K, V]{key: key, value: value})
+	return true
+}
+
+// evict removes the page at position i, moving the last page into its place.
+func (r *Random[K, V]) evict(i int) {
+	delete(r.entries, r.resident[i].key)
+	last := len(r.resident) - 1
+	if i != last {
+		r.resident[i] = r.resident[last]
+		r.entries[r.resident[i].key] = i
+	}
+	r.resident[last] = randomEntry[K, V]{}
+	r.resident = r.resident[:last]
+}
+
+func (r *Random[K, V]) Len() int {
+	return len(r.resident)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (r *Random[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   r.hits,
+		Misses: r.misses,
+	}
+}
@@ -0,0 +1,106 @@
+/******************************************************************************
+ * baseline_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for baseline.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that FIFO evicts the first page added, even if it was used since
+func TestFIFOEviction(t *testing.T) {
+	fifo := NewFIFO[string, []byte](limit, pages)
+
+	addCache(fifo, 1, 8)
+	fifo.Get("key1")
+	addCache(fifo, 9, 9)
+	if _, ok := fifo.queue.Peek("key1"); ok {
+		t.Errorf("Failed to evict the first page added. Key is: %s", "key1")
+		t.FailNow()
+	}
+	addCache(fifo, 2, 2)
+	addCache(fifo, 10, 10)
+	if fifo.queue.Contains("key2") || !fifo.queue.Contains("key3") {
+		t.Errorf("Failed to keep the order on setting a cached key. Key is: %s", "key2")
+		t.FailNow()
+	}
+	if stats := fifo.Stats(); stats.Hits != 1 {
+		t.Errorf("Miscounted hits. Hits are: %d", stats.Hits)
+		t.FailNow()
+	}
+}
+
+// Checks that MRU evicts the most recently used page
+func TestMRUEviction(t *testing.T) {
+	mru := NewMRU[string, []byte](limit, pages)
+
+	addCache(mru, 1, 8)
+	mru.Get("key3")
+	addCache(mru, 9, 9)
+	if mru.recency.Contains("key3") || mru.Len() != pages {
+		t.Errorf("Failed to evict the most recently used page. Key is: %s", "key3")
+		t.FailNow()
+	}
+	addCache(mru, 10, 10)
+	if mru.recency.Contains("key9") {
+		t.Errorf("Failed to evict the most recently added page. Key is: %s", "key9")
+		t.FailNow()
+	}
+}
+
+// Checks that Random keeps its bound and is reproducible with a seed
+func TestRandomEviction(t *testing.T) {
+	evicted := func(seed int64) []string {
+		r := NewRandom[string, []byte](limit, pages, seed)
+		addCache(r, 1, 32)
+		if r.Len() != pages || len(r.entries) != pages {
+			t.Errorf("Broke the size bound. Length is: %d", r.Len())
+			t.FailNow()
+		}
+		for key, i := range r.entries {
+			if r.resident[i].key != key {
+				t.Errorf("Lost track of a page. Key is: %s", key)
+				t.FailNow()
+			}
+		}
+		var kept []string
+		for i := 1; i <= 32; i++ {
+			if _, ok := r.entries[fmt.Sprintf("key%d", i)]; ok {
+				kept = append(kept, fmt.Sprintf("key%d", i))
+			}
+		}
+		return kept
+	}
+
+	a, b := evicted(1), evicted(1)
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Errorf("Failed to reproduce evictions with a seed. Kept: %v and %v", a, b)
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkFIFO(b *testing.B) {
+	benchHits(b, NewFIFO[int, int](1024, 64))
+}
+
+func BenchmarkMRU(b *testing.B) {
+	benchHits(b, NewMRU[int, int](1024, 64))
+}
+
+func BenchmarkRandom(b *testing.B) {
+	benchHits(b, NewRandom[int, int](1024, 64, 1))
+}
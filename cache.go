@@ -1,4 +1,7 @@
-package main
+// Package cache implements the Adaptive Replacement Cache (ARC) along with
+// an LRU cache to compare it against. Caches are generic over any comparable
+// key type and any value type.
+package cache
 
 type Stats struct {
 	Hits   int
@@ -15,7 +18,7 @@ func (stats *Stats) Equals(other *Stats) bool {
 	return stats.Hits == other.Hits && stats.Misses == other.Misses
 }
 
-type Cache interface {
+type Cache[K comparable, V any] interface {
 	// MaxStorage returns the maximum number of pages a cache can store
 	MaxPages() int
 
@@ -25,11 +28,11 @@ type Cache interface {
 	// Get returns the value associated with the given key, if it exists.
 	// This operation counts as a "use" for that key-value pair
 	// ok is true if a value was found and false otherwise.
-	Get(key string) (value []byte, ok bool)
+	Get(key K) (value V, ok bool)
 
 	// Set associates the given value with the given key in the cache and follows the cache's
 	// eviction protocol if the cache is full.
-	Set(key string, value []byte) bool
+	Set(key K, value V) bool
 
 	// Len returns the number of pages in the cache.
 	Len() int
@@ -38,3 +41,22 @@ type Cache interface {
 	// and misses this cache has resolved over its lifetime.
 	Stats() *Stats
 }
+
+// A SizeFunc returns the number of bytes a binding takes up in the cache.
+type SizeFunc[K comparable, V any] func(key K, value V) int
+
+// DefaultSize counts the length of string and []byte keys and values.
+// Keys and values of any other type are not counted, so they always fit in a page.
+func DefaultSize[K comparable, V any](key K, value V) int {
+	return sizeOf(key) + sizeOf(value)
+}
+
+func sizeOf(x any) int {
+	switch x := x.(type) {
+	case string:
+		return len(x)
+	case []byte:
+		return len(x)
+	}
+	return 0
+}
@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"math"
+)
+
+// The experts of a CACHEUS.
+const (
+	cacheusSRLRU = iota
+	cacheusCRLFU
+)
+
+const (
+	cacheusMinRate = 0.001 // Bounds of the learning rate
+	cacheusMaxRate = 1
+	cacheusRestart = 10 // Windows without change in hit ratio before the rate restarts
+)
+
+// A CACHEUS is the policy of Rodriguez et al. (FAST 2021), which builds on
+// LeCaR with two experts that resist the workloads LRU and LFU are bad at,
+// and with a learning rate that adapts itself.
+//
+// SR-LRU is a scan-resistant LRU: new pages enter a list SR, and only pages
+// used again move to a list R, whose LRU pages are demoted back to SR when R
+// outgrows its share. SR-LRU evicts the LRU page of SR, and grows SR when a
+// page it evicted as new is requested again and shrinks it when a page it
+// evicted after demoting it is. CR-LFU is a churn-resistant LFU that breaks
+// ties between the least frequently used pages by evicting the newest, so
+// a working set larger than the cache keeps part of itself cached.
+//
+// The learning rate is adapted by hill climbing once every pages lookups:
+// it keeps moving the same way while the hit ratio improves and turns back
+// when it worsens, and restarts at random if the hit ratio stops changing.
+//
+// Time is counted in lookups.
+type CACHEUS[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	sr_pages       int // SR's share of the pages; R may hold the rest
+
+	clock     int
+	sr        *LRU[K, V]        // Pages used once, or demoted from R
+	r         *LRU[K, V]        // Pages used again while in SR
+	demoted   map[K]struct{}    // Pages in SR that were demoted from R
+	frequency *LFU[K, struct{}] // Every page, by use count
+	history   [2]*LRU[K, cacheusGhost]
+	experts   experts
+
+	window_hits int     // Hits in the current window
+	last_ratio  float64 // Hit ratio of the last window
+	step        float64 // Last change of the learning rate, as a factor
+	unchanged   int     // Windows in a row without change in hit ratio
+
+	hits   int
+	misses int
+}
+
+// A cacheusGhost remembers when a key was evicted, and whether SR-LRU had
+// demoted it from R.
+type cacheusGhost struct {
+	time    int
+	demoted bool
+}
+
+// NewCACHEUS returns a CACHEUS holding pages bindings of up to limit/pages
+// bytes, choosing experts and restarting the learning rate with the given
+// seed. SR starts with half the pages and each expert remembers as many
+// keys as there are pages.
+func NewCACHEUS[K comparable, V any](limit int, pages int, seed int64) *CACHEUS[K, V] {
+	cacheus := &CACHEUS[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		sr_pages:       max(pages/2, 1),
+		sr:             NewLru[K, V](limit, pages),
+		r:              NewLru[K, V](limit, pages),
+		demoted:        make(map[K]struct{}),
+		frequency:      NewLFU[K, struct{}](limit, pages, 0),
+		history:        [2]*LRU[K, cacheusGhost]{newHistory[K, cacheusGhost](pages), newHistory[K, cacheusGhost](pages)},
+		step:           math.Sqrt2,
+	}
+	cacheus.experts = newExperts(pages, 0, seed)
+	cacheus.experts.rate = cacheusMinRate + (cacheusMaxRate-cacheusMinRate)*cacheus.experts.rand.Float64()
+	return cacheus
+}
+
+func (cacheus *CACHEUS[K, V]) MaxPages() int {
+	return cacheus.num_pages
+}
+
+func (cacheus *CACHEUS[K, V]) RemainingPages() int {
+	return cacheus.num_pages - cacheus.Len()
+}
+
+// Get returns the value of key, moving it to the MRU end of R and counting a
+// use of it.
+func (cacheus *CACHEUS[K, V]) Get(key K) (value V, ok bool) {
+	cacheus.clock += 1
+	defer cacheus.adapt()
+
+	if v, ok := cacheus.r.Peek(key); ok {
+		cacheus.r.Set(key, v)
+		value = v
+	} else if v, ok := cacheus.sr.Remove(key); ok {
+		delete(cacheus.demoted, key)
+		cacheus.r.Set(key, v)
+		cacheus.balance()
+		value = v
+	} else {
+		cacheus.misses += 1
+		return value, false
+	}
+	cacheus.frequency.touch(cacheus.frequency.entries[key])
+	cacheus.hits += 1
+	cacheus.window_hits += 1
+	return value, true
+}
+
+// Set adds the binding to SR, learning from the histories and evicting the
+// page chosen by one of the experts if the cache is full. Setting a cached
+// key replaces its value where it is.
+func (cacheus *CACHEUS[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > cacheus.bytes_per_page {
+		return false
+	}
+	for _, list := range []*LRU[K, V]{cacheus.sr, cacheus.r} {
+		if list.Contains(key) {
+			list.update(key, value)
+			return true
+		}
+	}
+	for expert, history := range cacheus.history {
+		ghost, ok := history.Remove(key)
+		if !ok {
+			continue
+		}
+		cacheus.experts.regret(expert, cacheus.clock-ghost.time)
+		if expert == cacheusSRLRU && ghost.demoted {
+			cacheus.sr_pages = max(cacheus.sr_pages-1, 1)
+		} else if expert == cacheusSRLRU {
+			cacheus.sr_pages = min(cacheus.sr_pages+1, max(cacheus.num_pages-1, 1))
+		}
+	}
+
+	if cacheus.Len() == cacheus.num_pages {
+		cacheus.evict()
+	}
+	cacheus.sr.Set(key, value)
+	cacheus.frequency.insert(key, struct{}{})
+	cacheus.balance()
+	return true
+}
+
+// evict removes the page chosen by one of the experts and remembers it in
+// that expert's history.
+func (cacheus *CACHEUS[K, V]) evict() {
+	expert := cacheus.experts.choose()
+	var victim K
+	if expert == cacheusCRLFU {
+		victim = cacheus.frequency.victim(true)
+	} else if key, ok := cacheus.sr.oldest(); ok {
+		victim = key
+	} else {
+		victim, _ = cacheus.r.oldest()
+	}
+	_, demoted := cacheus.demoted[victim]
+	delete(cacheus.demoted, victim)
+	cacheus.sr.Remove(victim)
+	cacheus.r.Remove(victim)
+	cacheus.frequency.remove(victim)
+	cacheus.history[expert].Set(victim, cacheusGhost{time: cacheus.clock, demoted: demoted})
+}
+
+// balance demotes the LRU pages of R to SR while R holds more than its share.
+func (cacheus *CACHEUS[K, V]) balance() {
+	for cacheus.r.Len() > cacheus.num_pages-cacheus.sr_pages {
+		key, value := cacheus.r.RemoveLRU()
+		cacheus.sr.Set(key, value)
+		cacheus.demoted[key] = struct{}{}
+	}
+}
+
+// adapt ends a window every pages lookups, moving the learning rate the same
+// way as last time if the hit ratio improved and back if it worsened.
+func (cacheus *CACHEUS[K, V]) adapt() {
+	if cacheus.clock%cacheus.num_pages != 0 {
+		return
+	}
+	ratio := float64(cacheus.window_hits) / float64(cacheus.num_pages)
+	cacheus.window_hits = 0
+	x := &cacheus.experts
+
+	switch {
+	case ratio == cacheus.last_ratio:
+		cacheus.unchanged += 1
+		if cacheus.unchanged >= cacheusRestart {
+			x.rate = cacheusMinRate + (cacheusMaxRate-cacheusMinRate)*x.rand.Float64()
+			cacheus.unchanged = 0
+		}
+	case ratio < cacheus.last_ratio:
+		cacheus.step = 1 / cacheus.step
+		cacheus.unchanged = 0
+	default:
+		cacheus.unchanged = 0
+	}
+	x.rate = min(max(x.rate*cacheus.step, cacheusMinRate), cacheusMaxRate)
+	cacheus.last_ratio = ratio
+}
+
+func (cacheus *CACHEUS[K, V]) Len() int {
+	return cacheus.sr.Len() + cacheus.r.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (cacheus *CACHEUS[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   cacheus.hits,
+		Misses: cacheus.misses,
+	}
+}
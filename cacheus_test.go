@@ -0,0 +1,133 @@
+/******************************************************************************
+ * cacheus_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for cacheus.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that reused pages move to R and that R's LRU pages are demoted to
+// SR when R outgrows its share
+func TestCACHEUSDemotion(t *testing.T) {
+	cacheus := NewCACHEUS[string, []byte](limit, pages, 1)
+
+	addCache(cacheus, 1, 8)
+	for i := 1; i <= 4; i++ {
+		cacheus.Get(fmt.Sprintf("key%d", i))
+	}
+	if cacheus.sr.Len() != 4 || cacheus.r.Len() != 4 {
+		t.Errorf("Failed to move reused pages to R. Lengths are: %d and %d", cacheus.sr.Len(), cacheus.r.Len())
+		t.FailNow()
+	}
+
+	cacheus.Get("key5")
+	if cacheus.r.Contains("key1") || !cacheus.sr.Contains("key1") {
+		t.Errorf("Failed to demote the LRU page of R. Key is: %s", "key1")
+		t.FailNow()
+	}
+	if _, ok := cacheus.demoted["key1"]; !ok {
+		t.Errorf("Failed to mark a demoted page. Key is: %s", "key1")
+		t.FailNow()
+	}
+	cacheus.Get("key1")
+	if _, ok := cacheus.demoted["key1"]; ok || !cacheus.r.Contains("key1") {
+		t.Errorf("Failed to promote a demoted page again. Key is: %s", "key1")
+		t.FailNow()
+	}
+}
+
+// Checks that each expert evicts the page its policy picks
+func TestCACHEUSEviction(t *testing.T) {
+	// SR-LRU evicts the LRU page of SR, not of R
+	cacheus := NewCACHEUS[string, []byte](limit, pages, 1)
+	cacheus.experts.weight = [2]float64{1, 0}
+	addCache(cacheus, 1, 8)
+	cacheus.Get("key1")
+	addCache(cacheus, 9, 9)
+	if cacheus.Len() != pages || !cacheus.r.Contains("key1") || !cacheus.history[cacheusSRLRU].Contains("key2") {
+		t.Errorf("Failed to evict the LRU page of SR. Key is: %s", "key2")
+		t.FailNow()
+	}
+
+	// CR-LFU evicts the newest of the least frequently used pages
+	cacheus = NewCACHEUS[string, []byte](limit, pages, 1)
+	cacheus.experts.weight = [2]float64{0, 1}
+	addCache(cacheus, 1, 8)
+	cacheus.Get("key8")
+	addCache(cacheus, 9, 9)
+	if cacheus.Len() != pages || !cacheus.history[cacheusCRLFU].Contains("key7") {
+		t.Errorf("Failed to evict the newest least frequently used page. Key is: %s", "key7")
+		t.FailNow()
+	}
+}
+
+// Checks that SR grows when a page evicted from it new is requested again,
+// and shrinks when a page evicted after demotion is
+func TestCACHEUSResize(t *testing.T) {
+	cacheus := NewCACHEUS[string, []byte](limit, pages, 1)
+	cacheus.experts.weight = [2]float64{1, 0}
+
+	addCache(cacheus, 1, 9)
+	size := cacheus.sr_pages
+	addCache(cacheus, 1, 1)
+	if cacheus.sr_pages != size+1 {
+		t.Errorf("Failed to grow SR on a new page's history hit. SR pages are: %d", cacheus.sr_pages)
+		t.FailNow()
+	}
+
+	cacheus.history[cacheusSRLRU].Set("key100", cacheusGhost{time: cacheus.clock, demoted: true})
+	addCache(cacheus, 100, 100)
+	if cacheus.sr_pages != size {
+		t.Errorf("Failed to shrink SR on a demoted page's history hit. SR pages are: %d", cacheus.sr_pages)
+		t.FailNow()
+	}
+}
+
+// Checks the bounds on a random workload, and that the learning rate stays
+// within its bounds
+func TestCACHEUSInvariants(t *testing.T) {
+	cacheus := NewCACHEUS[int, int](1024, 16, 1)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i
+		}
+		if _, ok := cacheus.Get(key); !ok {
+			cacheus.Set(key, key)
+		}
+		if cacheus.Len() > 16 || cacheus.frequency.Len() != cacheus.Len() {
+			t.Errorf("Broke the size bound. Lengths are: %d and %d", cacheus.Len(), cacheus.frequency.Len())
+			t.FailNow()
+		}
+		if cacheus.r.Len() > 16-cacheus.sr_pages || cacheus.sr_pages < 1 || cacheus.sr_pages > 15 {
+			t.Errorf("Broke the bound of R. Lengths are: %d and %d", cacheus.sr.Len(), cacheus.r.Len())
+			t.FailNow()
+		}
+		if rate := cacheus.experts.rate; rate < cacheusMinRate || rate > cacheusMaxRate {
+			t.Errorf("Broke the bounds of the learning rate. Rate is: %f", rate)
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkCACHEUS(b *testing.B) {
+	benchHits(b, NewCACHEUS[int, int](1024, 64, 1))
+}
@@ -0,0 +1,147 @@
+package cache
+
+// A CAR is Clock with Adaptive Replacement (Bansal and Modha, FAST 2004).
+// It keeps ARC's four lists and its adaptive target p, but T1 and T2 are
+// clocks, so a hit only sets a reference bit instead of moving the page.
+// Ghost hits in B1 and B2 adapt p exactly as in ARC.Set.
+type CAR[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	p              int // Target size of T1
+
+	t1 *clockList[K, V]  // Pages seen once recently
+	t2 *clockList[K, V]  // Pages seen at least twice recently
+	b1 *LRU[K, struct{}] // Keys evicted from T1
+	b2 *LRU[K, struct{}] // Keys evicted from T2
+
+	hits   int
+	misses int
+}
+
+// NewCAR returns a CAR holding pages bindings of up to limit/pages bytes.
+func NewCAR[K comparable, V any](limit int, pages int) *CAR[K, V] {
+	return &CAR[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		t1:             newClockList[K, V](),
+		t2:             newClockList[K, V](),
+		b1:             newGhost[K](pages),
+		b2:             newGhost[K](pages),
+	}
+}
+
+// newGhost returns an LRU of up to pages keys without values.
+func newGhost[K comparable](pages int) *LRU[K, struct{}] {
+	return newHistory[K, struct{}](pages)
+}
+
+// newHistory returns an LRU of up to pages keys with small values, such as
+// counts, that take no space of their own.
+func newHistory[K comparable, V any](pages int) *LRU[K, V] {
+	history := NewLru[K, V](pages, pages)
+	history.size = func(K, V) int { return 0 }
+	return history
+}
+
+func (car *CAR[K, V]) MaxPages() int {
+	return car.num_pages
+}
+
+func (car *CAR[K, V]) RemainingPages() int {
+	return car.num_pages - car.Len()
+}
+
+// Get returns the value of key if it is in T1 or T2, setting its reference bit.
+func (car *CAR[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := car.t1.get(key)
+	if !ok {
+		entry, ok = car.t2.get(key)
+	}
+	if !ok {
+		car.misses += 1
+		return value, false
+	}
+	entry.ref = true
+	car.hits += 1
+	return entry.value, true
+}
+
+// Set adds the binding following the CAR paper, on a miss in T1 and T2.
+func (car *CAR[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > car.bytes_per_page {
+		return false
+	}
+	entry, ok := car.t1.get(key)
+	if !ok {
+		entry, ok = car.t2.get(key)
+	}
+	if ok {
+		entry.value = value
+		entry.ref = true
+		return true
+	}
+
+	inB1, inB2 := car.b1.Contains(key), car.b2.Contains(key)
+	if car.Len() == car.num_pages {
+		car.replace()
+		// Keep the directory within 2c, and L1 within c
+		if !inB1 && !inB2 {
+			if car.t1.Len()+car.b1.Len() == car.num_pages {
+				car.b1.RemoveLRU()
+			} else if car.t1.Len()+car.t2.Len()+car.b1.Len()+car.b2.Len() == 2*car.num_pages {
+				car.b2.RemoveLRU()
+			}
+		}
+	}
+
+	switch {
+	case inB1:
+		car.p = growTarget(car.p, car.num_pages, 1, car.b1.Len(), car.b2.Len())
+		car.b1.Remove(key)
+		car.t2.push(&clockEntry[K, V]{key: key, value: value})
+	case inB2:
+		car.p = shrinkTarget(car.p, 1, car.b1.Len(), car.b2.Len())
+		car.b2.Remove(key)
+		car.t2.push(&clockEntry[K, V]{key: key, value: value})
+	default:
+		car.t1.push(&clockEntry[K, V]{key: key, value: value})
+	}
+	return true
+}
+
+// replace sweeps the clocks until it demotes a page to a ghost list. Pages
+// with their bit set in T1 have been hit since insertion, so they move to T2.
+func (car *CAR[K, V]) replace() {
+	for {
+		if car.t1.Len() >= max(1, car.p) {
+			head := car.t1.head()
+			if !head.ref {
+				car.b1.Set(car.t1.pop().key, struct{}{})
+				return
+			}
+			car.t1.pop()
+			head.ref = false
+			car.t2.push(head)
+		} else {
+			head := car.t2.head()
+			if !head.ref {
+				car.b2.Set(car.t2.pop().key, struct{}{})
+				return
+			}
+			head.ref = false
+			car.t2.advance()
+		}
+	}
+}
+
+func (car *CAR[K, V]) Len() int {
+	return car.t1.Len() + car.t2.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (car *CAR[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   car.hits,
+		Misses: car.misses,
+	}
+}
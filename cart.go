@@ -0,0 +1,174 @@
+package cache
+
+// A CART is CAR with Temporal filtering (Bansal and Modha, FAST 2004).
+//
+// ARC and CAR promote a page to their frequency list as soon as it is hit
+// twice, so two correlated references in quick succession are enough to make
+// a page look frequently used. CART instead gives every page a filter bit
+// marking it short-term (S) or long-term (L). New pages start as S and only
+// become L when they are hit again after staying in T1 for a while, or when
+// they come back from history. T2 only ever holds L pages.
+//
+// Besides CAR's target p for T1, CART adapts a target q for B1, which decides
+// which ghost list loses its oldest key when history is full.
+type CART[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	p              int // Target size of T1
+	q              int // Target size of B1
+	nS             int // Short-term pages in T1 and T2
+	nL             int // Long-term pages in T1 and T2
+
+	t1 *clockList[K, V]  // Recent pages, both S and L
+	t2 *clockList[K, V]  // Long-term pages that were hit in T1 or came from T2
+	b1 *LRU[K, struct{}] // Short-term pages evicted from T1
+	b2 *LRU[K, struct{}] // Long-term pages evicted from T2
+
+	hits   int
+	misses int
+}
+
+// NewCART returns a CART holding pages bindings of up to limit/pages bytes.
+func NewCART[K comparable, V any](limit int, pages int) *CART[K, V] {
+	return &CART[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		t1:             newClockList[K, V](),
+		t2:             newClockList[K, V](),
+		b1:             newGhost[K](pages + 1),
+		b2:             newGhost[K](pages + 1),
+	}
+}
+
+func (cart *CART[K, V]) MaxPages() int {
+	return cart.num_pages
+}
+
+func (cart *CART[K, V]) RemainingPages() int {
+	return cart.num_pages - cart.Len()
+}
+
+// Get returns the value of key if it is in T1 or T2, setting its reference bit.
+func (cart *CART[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := cart.t1.get(key)
+	if !ok {
+		entry, ok = cart.t2.get(key)
+	}
+	if !ok {
+		cart.misses += 1
+		return value, false
+	}
+	entry.ref = true
+	cart.hits += 1
+	return entry.value, true
+}
+
+// Set adds the binding following the CART paper, on a miss in T1 and T2.
+func (cart *CART[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > cart.bytes_per_page {
+		return false
+	}
+	entry, ok := cart.t1.get(key)
+	if !ok {
+		entry, ok = cart.t2.get(key)
+	}
+	if ok {
+		entry.value = value
+		entry.ref = true
+		return true
+	}
+
+	c := cart.num_pages
+	inB1, inB2 := cart.b1.Contains(key), cart.b2.Contains(key)
+	if cart.Len() == c {
+		cart.replace()
+		// History replacement: keep |B1| + |B2| within c + 1, trimming B1
+		// while it is over its target q
+		if !inB1 && !inB2 && cart.b1.Len()+cart.b2.Len() == c+1 {
+			if cart.b1.Len() > max(0, cart.q) || cart.b2.Len() == 0 {
+				cart.b1.RemoveLRU()
+			} else {
+				cart.b2.RemoveLRU()
+			}
+		}
+	}
+
+	switch {
+	case inB1:
+		cart.p = min(cart.p+max(1, cart.nS/cart.b1.Len()), c)
+		cart.b1.Remove(key)
+		cart.t1.push(&clockEntry[K, V]{key: key, value: value, long: true})
+		cart.nL++
+	case inB2:
+		cart.p = max(cart.p-max(1, cart.nL/cart.b2.Len()), 0)
+		cart.b2.Remove(key)
+		cart.t1.push(&clockEntry[K, V]{key: key, value: value, long: true})
+		cart.nL++
+		cart.growQ()
+	default:
+		cart.t1.push(&clockEntry[K, V]{key: key, value: value})
+		cart.nS++
+	}
+	return true
+}
+
+// growQ raises the target size of B1 when there are many long-term pages.
+func (cart *CART[K, V]) growQ() {
+	if cart.t2.Len()+cart.b2.Len()+cart.t1.Len()-cart.nS >= cart.num_pages {
+		cart.q = min(cart.q+1, 2*cart.num_pages-cart.t1.Len())
+	}
+}
+
+// shrinkQ lowers the target size of B1 as pages move into the long term.
+func (cart *CART[K, V]) shrinkQ() {
+	cart.q = max(cart.q-1, cart.num_pages-cart.t1.Len())
+}
+
+// replace demotes one page to a ghost list. Referenced pages in T2 go back
+// to T1, and the hand sweeps T1 until its head is an unreferenced S page,
+// moving L pages to T2 on the way.
+func (cart *CART[K, V]) replace() {
+	for cart.t2.Len() > 0 && cart.t2.head().ref {
+		head := cart.t2.pop()
+		head.ref = false
+		cart.t1.push(head)
+		cart.growQ()
+	}
+
+	for cart.t1.Len() > 0 && (cart.t1.head().long || cart.t1.head().ref) {
+		head := cart.t1.pop()
+		if head.ref {
+			head.ref = false
+			cart.t1.push(head)
+			if cart.t1.Len() >= min(cart.p+1, cart.b1.Len()) && !head.long {
+				head.long = true
+				cart.nS--
+				cart.nL++
+				cart.shrinkQ()
+			}
+		} else {
+			cart.t2.push(head)
+			cart.shrinkQ()
+		}
+	}
+
+	if cart.t1.Len() >= max(1, cart.p) {
+		cart.b1.Set(cart.t1.pop().key, struct{}{})
+		cart.nS--
+	} else {
+		cart.b2.Set(cart.t2.pop().key, struct{}{})
+		cart.nL--
+	}
+}
+
+func (cart *CART[K, V]) Len() int {
+	return cart.t1.Len() + cart.t2.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (cart *CART[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   cart.hits,
+		Misses: cart.misses,
+	}
+}
@@ -0,0 +1,112 @@
+/******************************************************************************
+ * cart_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for cart.go, following the behaviours described
+ *    in the CAR/CART paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that a page hit right after it is added is not promoted by CART
+// while history shows T1 is too small, where CAR would move it to T2
+func TestCARTCorrelatedReferences(t *testing.T) {
+	cart := NewCART[string, []byte](limit, pages)
+	car := NewCAR[string, []byte](limit, pages)
+
+	for _, c := range []Cache[string, []byte]{cart, car} {
+		// Fill history, then grow p with ghost hits on key1-4
+		addCache(c, 1, 16)
+		addCache(c, 1, 4)
+		// Correlated references: key50 is hit right after it is added
+		addCache(c, 50, 50)
+		c.Get("key50")
+		// Move the hand past key50
+		addCache(c, 51, 60)
+	}
+
+	if !car.t2.Contains("key50") {
+		t.Errorf("CAR failed to promote a page hit twice. Key is: %s", "key50")
+		t.FailNow()
+	}
+	if cart.t2.Contains("key50") || !cart.b1.Contains("key50") {
+		t.Errorf("CART kept a page with only correlated references as long-term. Key is: %s", "key50")
+		t.FailNow()
+	}
+}
+
+// Checks that a long scan of new pages does not flush long-term pages
+func TestCARTScanResistance(t *testing.T) {
+	cart := NewCART[string, []byte](limit, pages)
+
+	// Make key1-4 long-term with hits spread over time
+	for round := 0; round < 4; round++ {
+		for i := 1; i <= 4; i++ {
+			key := fmt.Sprintf("key%d", i)
+			if _, ok := cart.Get(key); !ok {
+				cart.Set(key, []byte(key))
+			}
+		}
+		addCache(cart, 100+round*4, 103+round*4)
+	}
+
+	// Scan pages that are never seen again
+	addCache(cart, 1000, 1100)
+
+	hits := 0
+	for i := 1; i <= 4; i++ {
+		if _, ok := cart.Get(fmt.Sprintf("key%d", i)); ok {
+			hits++
+		}
+	}
+	if hits < 3 {
+		t.Errorf("Scan flushed long-term pages. Only %d of %d still hit", hits, 4)
+		t.FailNow()
+	}
+}
+
+// Checks the invariants of the CART paper on a random workload
+func TestCARTInvariants(t *testing.T) {
+	cart := NewCART[int, int](1024, 16)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := cart.Get(key); !ok {
+			cart.Set(key, key)
+		}
+
+		if cart.Len() > 16 || cart.b1.Len()+cart.b2.Len() > 17 {
+			t.Errorf("Broke the size bounds. T1 %d, T2 %d, B1 %d, B2 %d", cart.t1.Len(), cart.t2.Len(), cart.b1.Len(), cart.b2.Len())
+			t.FailNow()
+		}
+		if cart.nS+cart.nL != cart.Len() {
+			t.Errorf("Lost count of filter bits. nS %d + nL %d is not %d", cart.nS, cart.nL, cart.Len())
+			t.FailNow()
+		}
+		if cart.p < 0 || cart.p > 16 || cart.q < 0 || cart.q > 32 {
+			t.Errorf("Targets out of range. P is: %d, Q is: %d", cart.p, cart.q)
+			t.FailNow()
+		}
+	}
+	for e := cart.t2.queue.Front(); e != nil; e = e.Next() {
+		if !e.Value.(*clockEntry[int, int]).long {
+			t.Errorf("Found a short-term page in T2. Key is: %d", e.Value.(*clockEntry[int, int]).key)
+			t.FailNow()
+		}
+	}
+}
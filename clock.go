@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"container/list"
+)
+
+// A clockList is a circular list of bindings with reference bits, stored as
+// a queue whose front is the clock hand. A hit only sets the binding's bit,
+// so unlike an LRU nothing moves on the list.
+type clockList[K comparable, V any] struct {
+	queue   *list.List
+	entries map[K]*list.Element
+}
+
+type clockEntry[K comparable, V any] struct {
+	key   K
+	value V
+	ref   bool // Set on every hit, cleared as the hand passes
+	long  bool // CART's filter bit: whether the page has long-term utility
+	freq  int  // S3-FIFO's access count, capped at s3MaxFreq
+}
+
+func newClockList[K comparable, V any]() *clockList[K, V] {
+	return &clockList[K, V]{
+		queue:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+func (cl *clockList[K, V]) Len() int {
+	return cl.queue.Len()
+}
+
+func (cl *clockList[K, V]) Contains(key K) bool {
+	_, ok := cl.entries[key]
+	return ok
+}
+
+// get returns the entry for key, if any.
+func (cl *clockList[K, V]) get(key K) (*clockEntry[K, V], bool) {
+	e, ok := cl.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return e.Value.(*clockEntry[K, V]), true
+}
+
+// push adds an entry just behind the hand, so it is the last one examined.
+func (cl *clockList[K, V]) push(entry *clockEntry[K, V]) {
+	cl.entries[entry.key] = cl.queue.PushBack(entry)
+}
+
+// head returns the entry under the hand.
+func (cl *clockList[K, V]) head() *clockEntry[K, V] {
+	return cl.queue.Front().Value.(*clockEntry[K, V])
+}
+
+// pop removes and returns the entry under the hand.
+func (cl *clockList[K, V]) pop() *clockEntry[K, V] {
+	entry := cl.queue.Remove(cl.queue.Front()).(*clockEntry[K, V])
+	delete(cl.entries, entry.key)
+	return entry
+}
+
+// advance moves the hand past the entry under it.
+func (cl *clockList[K, V]) advance() {
+	cl.queue.MoveToBack(cl.queue.Front())
+}
+
+// A CLOCK approximates LRU with a single reference bit per page. To evict,
+// the hand sweeps the pages, clearing set bits, until it finds one whose bit
+// is clear.
+type CLOCK[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	pages          *clockList[K, V]
+
+	hits   int
+	misses int
+}
+
+// NewCLOCK returns a CLOCK holding pages bindings of up to limit/pages bytes.
+func NewCLOCK[K comparable, V any](limit int, pages int) *CLOCK[K, V] {
+	return &CLOCK[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		pages:          newClockList[K, V](),
+	}
+}
+
+func (clock *CLOCK[K, V]) MaxPages() int {
+	return clock.num_pages
+}
+
+func (clock *CLOCK[K, V]) RemainingPages() int {
+	return clock.num_pages - clock.pages.Len()
+}
+
+// Get returns the value of key and sets its reference bit.
+func (clock *CLOCK[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := clock.pages.get(key)
+	if !ok {
+		clock.misses += 1
+		return value, false
+	}
+	entry.ref = true
+	clock.hits += 1
+	return entry.value, true
+}
+
+// Set adds the binding behind the hand, evicting a page if the cache is full.
+func (clock *CLOCK[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > clock.bytes_per_page {
+		return false
+	}
+	if entry, ok := clock.pages.get(key); ok {
+		entry.value = value
+		entry.ref = true
+		return true
+	}
+	if clock.pages.Len() == clock.num_pages {
+		for clock.pages.head().ref {
+			clock.pages.head().ref = false
+			clock.pages.advance()
+		}
+		clock.pages.pop()
+	}
+	clock.pages.push(&clockEntry[K, V]{key: key, value: value})
+	return true
+}
+
+func (clock *CLOCK[K, V]) Len() int {
+	return clock.pages.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (clock *CLOCK[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   clock.hits,
+		Misses: clock.misses,
+	}
+}
@@ -0,0 +1,152 @@
+/******************************************************************************
+ * clock_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for clock.go and car.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that CLOCK gives referenced pages a second chance
+func TestCLOCKSecondChance(t *testing.T) {
+	clock := NewCLOCK[string, []byte](limit, pages)
+
+	for i := 1; i <= 8; i++ {
+		key := fmt.Sprintf("key%d", i)
+		clock.Set(key, []byte(key))
+	}
+	// Reference key1 and key2 so the hand passes over them
+	clock.Get("key1")
+	clock.Get("key2")
+	clock.Set("key9", []byte("key9"))
+
+	if _, ok := clock.Get("key3"); ok {
+		t.Errorf("Failed to evict the first unreferenced page. Key is: %s", "key3")
+		t.FailNow()
+	}
+	for _, key := range []string{"key1", "key2", "key9"} {
+		if _, ok := clock.Get(key); !ok {
+			t.Errorf("Failed to cache hit on a set key. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+	if clock.Len() != 8 || clock.RemainingPages() != 0 {
+		t.Errorf("Overfilled the cache. Length is: %d when it should be %d", clock.Len(), 8)
+		t.FailNow()
+	}
+}
+
+// Checks that CAR promotes referenced pages from T1 to T2 only when the
+// hand passes them, not on the hit itself
+func TestCARPromotion(t *testing.T) {
+	car := NewCAR[string, []byte](limit, pages)
+
+	addCache(car, 1, 8)
+	for i := 1; i <= 4; i++ {
+		car.Get(fmt.Sprintf("key%d", i))
+	}
+	if car.t1.Len() != 8 || car.t2.Len() != 0 {
+		t.Errorf("Moved a page on a hit. T1 is: %d, T2 is: %d", car.t1.Len(), car.t2.Len())
+		t.FailNow()
+	}
+
+	// The hand moves key1-4 to T2 and demotes key5 to B1
+	addCache(car, 9, 9)
+	if car.t1.Len() != 4 || car.t2.Len() != 4 || car.b1.Len() != 1 {
+		t.Errorf("Wrong list lengths. T1 is: %d, T2 is: %d, B1 is: %d", car.t1.Len(), car.t2.Len(), car.b1.Len())
+		t.FailNow()
+	}
+	if !car.b1.Contains("key5") {
+		t.Errorf("Failed to demote the first unreferenced page to B1. Key is: %s", "key5")
+		t.FailNow()
+	}
+}
+
+// Checks that ghost hits adapt p as in ARC
+func TestCARAdaptation(t *testing.T) {
+	car := NewCAR[string, []byte](limit, pages)
+
+	addCache(car, 1, 8)
+	for i := 1; i <= 4; i++ {
+		car.Get(fmt.Sprintf("key%d", i))
+	}
+	// Demote key5 and key6 to B1
+	addCache(car, 9, 10)
+
+	// A hit in B1 grows p and brings the key back into T2
+	addCache(car, 5, 5)
+	if car.p != 1 {
+		t.Errorf("Failed to update parameter. P is: %d when it should be %d", car.p, 1)
+		t.FailNow()
+	}
+	if !car.t2.Contains("key5") {
+		t.Errorf("Failed to move a ghost hit into T2. Key is: %s", "key5")
+		t.FailNow()
+	}
+	if car.Len() != 8 {
+		t.Errorf("Overfilled the cache. Length is: %d when it should be %d", car.Len(), 8)
+		t.FailNow()
+	}
+}
+
+// Checks that CAR stays within its directory bounds on a random workload
+func TestCARBounds(t *testing.T) {
+	car := NewCAR[int, int](1024, 16)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		key := r.Intn(64)
+		if _, ok := car.Get(key); !ok {
+			car.Set(key, key)
+		}
+		directory := car.Len() + car.b1.Len() + car.b2.Len()
+		if car.Len() > 16 || car.t1.Len()+car.b1.Len() > 16 || directory > 32 {
+			t.Errorf("Broke the directory bounds. T1 %d, T2 %d, B1 %d, B2 %d", car.t1.Len(), car.t2.Len(), car.b1.Len(), car.b2.Len())
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+// benchHits replays a skewed workload, setting keys on a miss.
+func benchHits(b *testing.B, c Cache[int, int]) {
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, 1<<16)
+	keys := make([]int, 1<<16)
+	for i := range keys {
+		keys[i] = int(zipf.Uint64())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := keys[i&(len(keys)-1)]
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, key)
+		}
+	}
+}
+
+func BenchmarkARC(b *testing.B) {
+	benchHits(b, NewARC[int, int](1<<12, 1<<12))
+}
+
+func BenchmarkCLOCK(b *testing.B) {
+	benchHits(b, NewCLOCK[int, int](1<<12, 1<<12))
+}
+
+func BenchmarkCAR(b *testing.B) {
+	benchHits(b, NewCAR[int, int](1<<12, 1<<12))
+}
@@ -0,0 +1,297 @@
+// Command cachesim replays a trace against one or more cache replacement
+// policies and prints their results side by side: object and byte hit
+// ratios, bytes fetched from the origin and bytes written into the cache.
+//
+// Usage:
+//
+//	cachesim [-policy arc,lru,opt] [-size bytes] [-pages pages] [-bytes] [-param name=value] <trace>
+//
+// The trace is streamed from the file, which may be compressed with gzip or
+// bzip2, or from standard input if it is "-". The trace format is detected
+// from its first line, or given with -trace-format: webcachesim
+// (`time id size`), csv with -columns, the spc and arc block traces, msr for
+// MSR Cambridge and twitter for Twitter's cache traces.
+//
+// With -bytes the cache holds -size bytes of objects whose sizes are read
+// from the third column of the trace, instead of -pages objects.
+//
+// Policies with tunable parameters read them from -param, which may be
+// repeated, e.g. -param 2q.kin=0.3; the usage message lists them.
+//
+// With -sweep min:max:steps the trace is instead replayed at every capacity
+// in the range, in pages or in bytes with -bytes, and the miss ratio curve
+// of each policy is written as CSV or JSON, chosen with -format
+// rather than -output:
+//
+//	cachesim -sweep 10:10000:20 -log -format json <trace>
+//
+// Adding -stack computes the LRU curve in a single pass from Mattson stack
+// distances instead of one replay per capacity, and -shards samples a
+// fraction of the keys for very large traces.
+//
+// With -output csv or -output json, the results are instead written as one
+// record per policy, with its parameters, the trace's name and checksum, how
+// the trace was read and how much of it was warm-up, the request counts and
+// hit ratios, and the wall time and ns/op of the run, so results from many
+// runs can be merged and compared.
+//
+// With -warmup n or -warmup-time seconds, the first requests of the trace
+// only fill the caches and are left out of the results. With -window n or
+// -window-time seconds, the hit ratios of every window of the remaining
+// requests are also written as CSV to -window-out, to plot phase changes.
+//
+// With -p-every n, ARC's target size p is sampled every n requests after
+// the warm-up and written as CSV to -p-out, to plot how ARC adapts across
+// the trace.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cachesim: ")
+
+	policyList := flag.String("policy", "arc,lru,opt", "comma separated list of policies to simulate")
+	size := flag.Int("size", 1024, "total number of bytes in the cache with -bytes")
+	pages := flag.Int("pages", 64, "total number of pages in the cache, each holding one object whatever its key")
+	byteMode := flag.Bool("bytes", false, "treat -size as a byte budget for objects of the sizes in the trace")
+	traceFormat := flag.String("trace-format", "auto", "trace format: auto, webcachesim, csv, spc, arc, msr or twitter")
+	columns := flag.String("columns", "time=0,key=1,size=2", "columns of a csv trace, leaving out absent fields")
+	header := flag.Bool("header", false, "skip the first line of a csv trace")
+	blockSize := flag.Int("block-size", 0, "bytes per block when splitting block traces (0 for the format's default)")
+	showProgress := flag.Bool("progress", false, "report requests simulated and requests/sec on stderr")
+	sweepSpec := flag.String("sweep", "", "write a miss ratio curve over capacities `min:max:steps` instead of a table")
+	logScale := flag.Bool("log", false, "space the -sweep capacities logarithmically")
+	format := flag.String("format", "csv", "miss ratio curve format: csv or json")
+	stack := flag.Bool("stack", false, "compute the -sweep lru curve in one pass from stack distances")
+	shards := flag.Float64("shards", 1, "fraction of keys sampled for -stack, as in SHARDS")
+	warmup := flag.Int("warmup", 0, "leave the first `n` requests out of the results")
+	warmupTime := flag.Float64("warmup-time", 0, "leave the first `seconds` of trace time out of the results")
+	windowSize := flag.Int("window", 0, "write the hit ratios of every `n` requests as CSV to -window-out")
+	windowTime := flag.Float64("window-time", 0, "write the hit ratios of every `seconds` of trace time as CSV to -window-out")
+	windowOut := flag.String("window-out", "windows.csv", "file the -window or -window-time hit ratios are written to")
+	output := flag.String("output", "table", "result format: table, csv or json records with the run's parameters and trace checksum (not with -sweep)")
+	pEvery := flag.Int("p-every", 0, "sample ARC's target size p every `n` requests (0 for never)")
+	pOut := flag.String("p-out", "p.csv", "file the -p-every samples are written to as CSV")
+	policyParams := paramList{}
+	flag.Var(policyParams, "param", "set a policy parameter `name=value`; may be repeated")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: cachesim [flags] <trace | ->\n\npolicies: %s\n\nparameters:\n", strings.Join(policyNames(), ", "))
+		for _, name := range paramNames() {
+			fmt.Fprintf(out, "  %s\n    \t%s (default %g)\n", name, params[name].doc, params[name].def)
+		}
+		fmt.Fprintf(out, "\nflags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *byteMode && *size <= 0 {
+		log.Fatalf("need a positive size, got size=%d", *size)
+	}
+	if !*byteMode && *pages <= 0 {
+		log.Fatalf("need a positive number of pages, got pages=%d", *pages)
+	}
+	names, err := parsePolicies(*policyList)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range names {
+		if *byteMode && !bytePolicies[name] {
+			log.Fatalf("policy %s counts pages and cannot be run with -bytes", name)
+		}
+	}
+
+	src := source{path: flag.Arg(0), format: new(trace.Format)}
+	if src.opts.Format, err = trace.ParseFormat(*traceFormat); err != nil {
+		log.Fatal(err)
+	}
+	if src.opts.Columns, err = parseColumns(*columns); err != nil {
+		log.Fatal(err)
+	}
+	src.opts.Columns.Header = *header
+	src.opts.BlockSize = *blockSize
+	if src.path == trace.Stdin && (needsFuture(names) || *sweepSpec != "") {
+		log.Fatal("standard input can only be read once, so offline policies and -sweep need a trace file")
+	}
+
+	cfg := config{size: *size, pages: *pages, byteMode: *byteMode, params: policyParams}
+	if needsFuture(names) {
+		if cfg.future, err = scanKeys(src); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *showProgress {
+		src.progress = startProgress(os.Stderr, time.Second)
+	}
+
+	if *output != "table" && *output != "csv" && *output != "json" {
+		log.Fatalf("unknown output %q (available: table, csv, json)", *output)
+	}
+	if *pEvery < 0 {
+		log.Fatalf("need a non-negative p-every, got %d", *pEvery)
+	}
+	ph := phases{warmup: *warmup, warmupTime: *warmupTime, window: *windowSize, windowTime: *windowTime}
+	if ph.warmup < 0 || ph.warmupTime < 0 || ph.window < 0 || ph.windowTime < 0 {
+		log.Fatal("need non-negative warm-up and window sizes")
+	}
+	if ph.window > 0 && ph.windowTime > 0 {
+		log.Fatal("-window and -window-time cannot be combined")
+	}
+	if *sweepSpec != "" {
+		if *pEvery > 0 || ph.windowed() {
+			log.Fatal("-p-every and windows follow a single run and cannot be combined with -sweep")
+		}
+		if *output != "table" {
+			log.Fatal("-output applies to single runs; -sweep writes its curves in the -format")
+		}
+		capacities, err := parseSweep(*sweepSpec, *logScale)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stackRate := 0.0
+		if *stack {
+			if *byteMode {
+				log.Fatal("-stack counts pages and cannot be combined with -bytes")
+			}
+			if ph.warmup > 0 || ph.warmupTime > 0 {
+				log.Fatal("-stack counts every request and cannot be combined with a warm-up")
+			}
+			if *shards <= 0 || *shards > 1 {
+				log.Fatalf("need 0 < shards <= 1, got %g", *shards)
+			}
+			stackRate = *shards
+		}
+		points, err := sweep(src, names, cfg, capacities, stackRate, ph)
+		src.progress.stop()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeCurve(os.Stdout, *format, points); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	runs := make([]*run, len(names))
+	for i, name := range names {
+		runs[i] = &run{name: name, cache: policies[name](cfg)}
+		if sampler, ok := runs[i].cache.(interface{ SampleTarget(int) }); ok {
+			sampler.SampleTarget(*pEvery)
+		}
+	}
+
+	start := time.Now()
+	err = replay(src, runs, ph)
+	wall := time.Since(start)
+	src.progress.stop()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "table" {
+		report(os.Stdout, runs)
+	} else {
+		recs, err := records(runs, cfg, src, ph, wall)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeRecords(os.Stdout, *output, recs); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if ph.windowed() {
+		if err := writeFile(*windowOut, func(w io.Writer) error { return writeWindows(w, runs) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *pEvery > 0 {
+		if err := writeFile(*pOut, func(w io.Writer) error { return writeTargets(w, runs, *pEvery) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// writeFile creates the file at path and writes it with write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replay feeds every request in the trace to each run. Requests in the
+// warm-up only fill the caches and are left out of the caches' own
+// statistics, and with windows each run also keeps the metrics of every
+// window. It fails if the warm-up takes the whole trace.
+func replay(src source, runs []*run, ph phases) error {
+	clock := &phaseClock{phases: ph}
+	src.timed = ph.timed()
+	err := src.each(func(req trace.Request) {
+		if clock.warming(req.Time) {
+			for _, r := range runs {
+				r.warm(req.Key, req.Size)
+			}
+			return
+		}
+		first := clock.counted == 0
+		if first && clock.seen > 1 {
+			for _, r := range runs {
+				r.endWarmup()
+			}
+		}
+		index := clock.next(req.Time)
+		if ph.windowed() && (first || index != clock.current) {
+			for _, r := range runs {
+				if !first {
+					r.closeWindow()
+				}
+				r.openWindow(index, clock.windowStart(index, req.Time))
+			}
+			clock.current = index
+		}
+		for _, r := range runs {
+			r.request(req.Key, req.Size)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if ph.windowed() && clock.counted > 0 {
+		for _, r := range runs {
+			r.closeWindow()
+		}
+	}
+	if clock.seen > 0 && clock.counted == 0 {
+		return fmt.Errorf("%s: all %d requests fell in the warm-up", src.path, clock.seen)
+	}
+	return nil
+}
+
+// scanKeys returns the key of every request in the trace, for offline
+// policies that need to know the future.
+func scanKeys(src source) ([]string, error) {
+	var keys []string
+	err := src.each(func(req trace.Request) {
+		keys = append(keys, req.Key)
+	})
+	return keys, err
+}
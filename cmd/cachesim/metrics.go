@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	cache "github.com/gleising/COS_Final_Project"
+)
+
+// A run is one policy being simulated over the trace.
+type run struct {
+	name    string
+	cache   simCache
+	metrics metrics
+
+	window      window   // The current window
+	windowStart metrics  // Metrics when the current window started
+	windows     []window // Every window ended
+}
+
+// metrics counts requests and bytes as the simulator sees them. A hit serves
+// the object's bytes from the cache; a miss fetches them from the origin and
+// then tries to write them into the cache.
+type metrics struct {
+	Requests     int
+	Hits         int
+	Misses       int
+	Bytes        int64 // Bytes requested
+	HitBytes     int64 // Bytes served from the cache
+	OriginBytes  int64 // Bytes fetched from the origin on a miss
+	WrittenBytes int64 // Bytes admitted into the cache
+
+	Elapsed time.Duration // Time spent in the cache's Get and Set
+}
+
+// request replays one request of size bytes against the run's cache.
+func (r *run) request(key string, size int) {
+	m := &r.metrics
+	m.Requests++
+	m.Bytes += int64(size)
+	start := time.Now()
+	_, hit := r.cache.Get(key)
+	written := !hit && r.cache.Set(key, size)
+	m.Elapsed += time.Since(start)
+
+	if hit {
+		m.Hits++
+		m.HitBytes += int64(size)
+		return
+	}
+	m.Misses++
+	m.OriginBytes += int64(size)
+	if written {
+		m.WrittenBytes += int64(size)
+	}
+}
+
+// NsPerOp returns the mean time the cache took to serve a request.
+func (m *metrics) NsPerOp() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return float64(m.Elapsed.Nanoseconds()) / float64(m.Requests)
+}
+
+// HitRatio returns the fraction of requests that were hits.
+func (m *metrics) HitRatio() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(m.Requests)
+}
+
+// ByteHitRatio returns the fraction of requested bytes served from the cache.
+func (m *metrics) ByteHitRatio() float64 {
+	if m.Bytes == 0 {
+		return 0
+	}
+	return float64(m.HitBytes) / float64(m.Bytes)
+}
+
+// arcReporter is a cache that reports ARC's extended statistics.
+type arcReporter interface {
+	ARCStats() *cache.ARCStats
+}
+
+// report prints one row of results per policy, including the mean time the
+// cache spent on each request. When OPT was simulated, each row also shows
+// how far its hit ratio is below OPT's. ARC also gets a second table of its
+// extended statistics.
+func report(out io.Writer, runs []*run) {
+	var opt *metrics
+	for _, r := range runs {
+		if r.name == "opt" {
+			opt = &r.metrics
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "Policy\tHits\tMisses\tRatio\tByte Ratio\tOrigin Bytes\tWritten Bytes\tns/op\t")
+	if opt != nil {
+		fmt.Fprint(w, "Gap to OPT\t")
+	}
+	fmt.Fprintln(w)
+	for _, r := range runs {
+		m := &r.metrics
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.4f\t%.4f\t%d\t%d\t%.0f\t", r.name, m.Hits, m.Misses, m.HitRatio(), m.ByteHitRatio(), m.OriginBytes, m.WrittenBytes, m.NsPerOp())
+		if opt != nil {
+			fmt.Fprintf(w, "%.4f\t", opt.HitRatio()-m.HitRatio())
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+	reportARC(out, runs)
+}
+
+// reportARC prints the extended statistics of each ARC simulated: its
+// evictions, promotions, ghost hits with an upper bound on the number of
+// them expected to be false positives of key fingerprints, rejected sets,
+// target size p and list sizes at the end of the trace.
+func reportARC(out io.Writer, runs []*run) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := false
+	for _, r := range runs {
+		a, ok := r.cache.(arcReporter)
+		if !ok {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out)
+			fmt.Fprintln(w, "Policy\tT1 Evictions\tT2 Evictions\tPromotions\tB1 Hits\tB2 Hits\tEst. False Positives\tRejected\tp\tMin p\tMax p\tMean p\t|T1|\t|T2|\t|B1|\t|B2|\t")
+			header = true
+		}
+		s := a.ARCStats()
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.1f\t%d\t%d\t%d\t%d\t%.1f\t%d\t%d\t%d\t%d\t\n", r.name,
+			s.T1Evictions, s.T2Evictions, s.Promotions, s.B1Hits, s.B2Hits, s.FalsePositives, s.Rejected,
+			s.P, s.MinP, s.MaxP, s.MeanP, s.T1Len, s.T2Len, s.B1Len, s.B2Len)
+	}
+	w.Flush()
+}
+
+// writeTargets writes the samples of p of each ARC simulated as CSV, one
+// row per sample with the number of requests counted after the warm-up
+// when it was taken.
+func writeTargets(out io.Writer, runs []*run, every int) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"policy", "requests", "p"})
+	for _, r := range runs {
+		a, ok := r.cache.(arcReporter)
+		if !ok {
+			continue
+		}
+		for i, p := range a.ARCStats().PSeries {
+			w.Write([]string{r.name, strconv.Itoa((i + 1) * every), strconv.Itoa(p)})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A param is a tunable policy parameter set with -param name=value.
+type param struct {
+	def float64 // Value used when the parameter is not set
+	doc string
+}
+
+// params lists the parameters -param accepts, named policy.parameter.
+var params = map[string]param{
+	"2q.kin":             {0.25, "fraction of the pages held by 2Q's A1in (A1 for 2q-simple)"},
+	"2q.kout":            {0.5, "keys remembered by 2Q's A1out, as a fraction of the pages"},
+	"arc.fingerprint":    {0, "bits of the key fingerprints ARC's ghost lists keep, up to 64; 0 keeps the keys"},
+	"arc.seed":           {1, "seed of the hash of ARC's key fingerprints"},
+	"cacheus.seed":       {1, "seed of CACHEUS's choice of expert and learning rate restarts"},
+	"gdsf.bandwidth":     {1e6, "bytes per second of the link gdsf-latency fetches misses over"},
+	"gdsf.rtt":           {0.05, "round trip time in seconds of the link gdsf-latency fetches misses over"},
+	"lecar.rate":         {0.45, "LeCaR's learning rate"},
+	"lecar.seed":         {1, "seed of LeCaR's choice of expert"},
+	"lfu.aging":          {0, "lookups between halvings of LFU's counts, as a multiple of the pages; 0 never ages"},
+	"lhd.seed":           {1, "seed of LHD's eviction sampling"},
+	"lirs.ghosts":        {2, "non-resident HIR keys LIRS remembers, as a fraction of the pages"},
+	"lirs.hir":           {0.01, "fraction of the pages held by LIRS's resident HIR pages"},
+	"lruk.crp":           {0, "LRU-K's correlated reference period, in lookups"},
+	"lruk.history":       {1, "evicted pages whose references LRU-K remembers, as a fraction of the pages"},
+	"lruk.k":             {2, "references LRU-K remembers per page"},
+	"mq.lifetime":        {1, "lookups before an unused MQ page drops a queue, as a multiple of the pages"},
+	"mq.qout":            {4, "evicted pages whose counts MQ remembers, as a multiple of the pages"},
+	"mq.queues":          {8, "number of MQ's LRU queues"},
+	"random.seed":        {1, "seed of Random's choice of page to evict"},
+	"s3fifo.small":       {0.1, "fraction of the pages held by S3-FIFO's small queue"},
+	"slru.segments":      {2, "number of segments of the segmented LRU"},
+	"wtinylfu.protected": {0.8, "fraction of W-TinyLFU's main space held by its protected segment"},
+	"wtinylfu.seed":      {1, "seed of the hash of W-TinyLFU's frequency sketch"},
+	"wtinylfu.window":    {0.01, "fraction of the pages held by W-TinyLFU's LRU window"},
+}
+
+// paramList collects every -param flag given.
+type paramList map[string]float64
+
+func (l paramList) String() string {
+	parts := make([]string, 0, len(l))
+	for name, value := range l {
+		parts = append(parts, fmt.Sprintf("%s=%g", name, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// Set reads a name=value pair, rejecting parameters no policy declares.
+func (l paramList) Set(spec string) error {
+	name, value, ok := strings.Cut(spec, "=")
+	if !ok {
+		return fmt.Errorf("expected name=value, got %q", spec)
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := params[name]; !ok {
+		return fmt.Errorf("unknown parameter %q (available: %s)", name, strings.Join(paramNames(), ", "))
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || v < 0 {
+		return fmt.Errorf("parameter %s: need a non-negative number, got %q", name, value)
+	}
+	l[name] = v
+	return nil
+}
+
+// paramNames returns the declared parameter names in sorted order.
+func paramNames() []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// param returns the value of a declared parameter, or its default.
+func (cfg config) param(name string) float64 {
+	if v, ok := cfg.params[name]; ok {
+		return v
+	}
+	return params[name].def
+}
+
+// pagesParam returns a parameter given as a fraction of the pages as a
+// number of pages.
+func (cfg config) pagesParam(name string) int {
+	return int(cfg.param(name) * float64(cfg.pages))
+}
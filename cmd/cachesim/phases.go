@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+)
+
+// phases splits a replay into a warm-up, whose requests fill the caches
+// but are left out of their metrics, and windows of the remaining requests,
+// whose metrics are also kept apart.
+type phases struct {
+	warmup     int     // Requests in the warm-up
+	warmupTime float64 // Seconds of trace time in the warm-up
+	window     int     // Requests per window, 0 for none
+	windowTime float64 // Seconds of trace time per window, 0 for none
+}
+
+// A window holds the metrics of one policy over a stretch of the trace.
+type window struct {
+	Policy       string
+	Index        int
+	FirstRequest int     // Requests counted before the window
+	StartTime    float64 // Trace time at which the window starts
+	Requests     int
+	Hits         int
+	HitRatio     float64
+	ByteHitRatio float64
+}
+
+// a phaseClock follows where a replay is in its phases.
+type phaseClock struct {
+	phases
+	seen    int     // Requests read, including the warm-up
+	counted int     // Requests counted after the warm-up
+	first   float64 // Trace time of the first request
+	start   float64 // Trace time of the first counted request
+	current int     // Index of the current window
+}
+
+// warming reports whether a request at trace time t is part of the warm-up,
+// counting it.
+func (c *phaseClock) warming(t float64) bool {
+	if c.seen == 0 {
+		c.first = t
+	}
+	c.seen++
+	if c.seen <= c.warmup || t-c.first < c.warmupTime {
+		return true
+	}
+	if c.counted == 0 {
+		c.start = t
+	}
+	return false
+}
+
+// next returns the window a counted request at trace time t falls in,
+// counting it.
+func (c *phaseClock) next(t float64) int {
+	c.counted++
+	if c.windowTime > 0 {
+		return int(math.Floor((t - c.start) / c.windowTime))
+	}
+	if c.window > 0 {
+		return (c.counted - 1) / c.window
+	}
+	return 0
+}
+
+// windowStart returns the trace time at which window i starts, or the time
+// of its first request for windows of a number of requests.
+func (c *phaseClock) windowStart(i int, t float64) float64 {
+	if c.windowTime > 0 {
+		return c.start + float64(i)*c.windowTime
+	}
+	return t
+}
+
+// timed reports whether the phases are measured in trace time.
+func (ph phases) timed() bool {
+	return ph.warmupTime > 0 || ph.windowTime > 0
+}
+
+// windowed reports whether the replay keeps metrics by window.
+func (ph phases) windowed() bool {
+	return ph.window > 0 || ph.windowTime > 0
+}
+
+// warm replays a warm-up request against the run's cache without counting
+// it.
+func (r *run) warm(key string, size int) {
+	if _, hit := r.cache.Get(key); !hit {
+		r.cache.Set(key, size)
+	}
+}
+
+// endWarmup makes caches that keep statistics of their own, like ARC's,
+// forget the warm-up, so those statistics count the same requests as the
+// run's metrics.
+func (r *run) endWarmup() {
+	if c, ok := r.cache.(interface{ ResetStats() }); ok {
+		c.ResetStats()
+	}
+}
+
+// openWindow starts a window of the run's metrics.
+func (r *run) openWindow(index int, start float64) {
+	r.windowStart = r.metrics
+	r.window = window{Policy: r.name, Index: index, FirstRequest: r.metrics.Requests, StartTime: start}
+}
+
+// closeWindow ends the current window, if it has any requests.
+func (r *run) closeWindow() {
+	m := metrics{
+		Requests: r.metrics.Requests - r.windowStart.Requests,
+		Hits:     r.metrics.Hits - r.windowStart.Hits,
+		Bytes:    r.metrics.Bytes - r.windowStart.Bytes,
+		HitBytes: r.metrics.HitBytes - r.windowStart.HitBytes,
+	}
+	if m.Requests == 0 {
+		return
+	}
+	w := r.window
+	w.Requests, w.Hits = m.Requests, m.Hits
+	w.HitRatio, w.ByteHitRatio = m.HitRatio(), m.ByteHitRatio()
+	r.windows = append(r.windows, w)
+}
+
+// writeWindows writes the windows of every run as CSV.
+func writeWindows(out io.Writer, runs []*run) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"policy", "window", "first_request", "start_time", "requests", "hits", "hit_ratio", "byte_hit_ratio"})
+	for _, r := range runs {
+		for _, win := range r.windows {
+			w.Write([]string{
+				win.Policy,
+				strconv.Itoa(win.Index),
+				strconv.Itoa(win.FirstRequest),
+				strconv.FormatFloat(win.StartTime, 'f', -1, 64),
+				strconv.Itoa(win.Requests),
+				strconv.Itoa(win.Hits),
+				strconv.FormatFloat(win.HitRatio, 'f', 6, 64),
+				strconv.FormatFloat(win.ByteHitRatio, 'f', 6, 64),
+			})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
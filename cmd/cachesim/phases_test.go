@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+// Writes a webcachesim trace of one request per second, cycling over keys
+func writeTrace(t *testing.T, requests int, keys int) source {
+	var b strings.Builder
+	for i := 0; i < requests; i++ {
+		fmt.Fprintf(&b, "%d %d 1\n", i, i%keys)
+	}
+	path := filepath.Join(t.TempDir(), "trace.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return source{path: path}
+}
+
+// Checks that warm-up requests fill the cache but are not counted
+func TestReplayWarmup(t *testing.T) {
+	src := writeTrace(t, 100, 10)
+	cfg := config{size: 100, pages: 10}
+	for _, ph := range []phases{{warmup: 10}, {warmupTime: 10}} {
+		r := &run{name: "lru", cache: policies["lru"](cfg)}
+		if err := replay(src, []*run{r}, ph); err != nil {
+			t.Fatal(err)
+		}
+		if r.metrics.Requests != 90 || r.metrics.Hits != 90 {
+			t.Errorf("replay with %+v counted %d requests and %d hits. Should be: 90 and 90", ph, r.metrics.Requests, r.metrics.Hits)
+		}
+	}
+}
+
+// Checks that ARC's own statistics and samples of p leave out the warm-up
+func TestReplayWarmupARC(t *testing.T) {
+	src := writeTrace(t, 100, 20)
+	cfg := config{size: 100, pages: 10}
+	r := &run{name: "arc", cache: policies["arc"](cfg)}
+	r.cache.(interface{ SampleTarget(int) }).SampleTarget(10)
+	if err := replay(src, []*run{r}, phases{warmup: 30}); err != nil {
+		t.Fatal(err)
+	}
+	s := r.cache.(arcReporter).ARCStats()
+	if s.Hits != r.metrics.Hits || s.Misses != r.metrics.Misses || len(s.PSeries) != 7 {
+		t.Errorf("ARC counted %d hits, %d misses and %d samples of p. Should be: %d, %d and 7", s.Hits, s.Misses, len(s.PSeries), r.metrics.Hits, r.metrics.Misses)
+	}
+}
+
+// Checks windows of a number of requests and of trace time
+func TestReplayWindows(t *testing.T) {
+	src := writeTrace(t, 100, 10)
+	cfg := config{size: 100, pages: 10}
+	for _, ph := range []phases{{window: 30}, {windowTime: 30}, {warmup: 5, window: 30}} {
+		r := &run{name: "lru", cache: policies["lru"](cfg)}
+		if err := replay(src, []*run{r}, ph); err != nil {
+			t.Fatal(err)
+		}
+		counted := 100 - ph.warmup
+		if len(r.windows) != (counted+29)/30 {
+			t.Errorf("replay with %+v made %d windows. Should be: %d", ph, len(r.windows), (counted+29)/30)
+			continue
+		}
+		total := 0
+		for i, w := range r.windows {
+			if w.Index != i || w.FirstRequest != total {
+				t.Errorf("replay with %+v numbered window %d as %d from request %d", ph, i, w.Index, w.FirstRequest)
+			}
+			total += w.Requests
+		}
+		if total != counted || r.windows[0].Hits != 30-10+ph.warmup {
+			t.Errorf("replay with %+v counted %d requests and %d hits in the first window", ph, total, r.windows[0].Hits)
+		}
+	}
+}
+
+// Checks that phases in trace time are rejected for traces without times,
+// and that a warm-up may not take the whole trace
+func TestReplayUntimed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.arc")
+	if err := os.WriteFile(path, []byte("0 1 0 0\n1 1 0 1\n0 1 0 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := source{path: path}
+	src.opts.Format = trace.ARC
+	cfg := config{size: 100, pages: 10}
+	for _, ph := range []phases{{warmupTime: 1}, {windowTime: 1}} {
+		r := &run{name: "lru", cache: policies["lru"](cfg)}
+		if err := replay(src, []*run{r}, ph); err == nil {
+			t.Errorf("replay with %+v should have failed on a trace without times", ph)
+		}
+	}
+
+	r := &run{name: "lru", cache: policies["lru"](cfg)}
+	if err := replay(src, []*run{r}, phases{warmup: 1}); err != nil || r.metrics.Requests != 2 {
+		t.Errorf("replay with a warm-up of 1 request counted %d requests: %v", r.metrics.Requests, err)
+	}
+	if err := replay(writeTrace(t, 10, 10), []*run{r}, phases{warmup: 10}); err == nil {
+		t.Errorf("replay should have failed when the warm-up takes the whole trace")
+	}
+}
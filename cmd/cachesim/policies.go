@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	cache "github.com/gleising/COS_Final_Project"
+)
+
+// config holds the parameters every policy is constructed from.
+type config struct {
+	size     int  // Total number of bytes in the cache
+	pages    int  // Total number of pages in the cache
+	byteMode bool // Whether size is a byte budget for variable-size objects
+
+	future []string  // Every key of the trace in order, for offline policies
+	params paramList // Policy parameters set with -param
+}
+
+// simCache is the cache type simulated: keys are trace object ids and values
+// are object sizes in bytes.
+type simCache = cache.Cache[string, int]
+
+// objectSize measures a cached object by the size recorded in the trace.
+func objectSize(key string, size int) int {
+	return size
+}
+
+// unitSize counts every object as one page, for policies that account
+// capacity in bytes when they are run without -bytes.
+func unitSize(key string, size int) int {
+	return 1
+}
+
+// pageLimit is the byte limit policies that count pages are built with.
+// They reject bindings of more than limit/pages bytes as measured by
+// cache.DefaultSize, which for the simulator's int values is the length of
+// the key. Trace keys name objects rather than hold them, so every page has
+// room for any key and only the number of pages bounds the cache.
+func (cfg config) pageLimit() int {
+	return math.MaxInt / cfg.pages * cfg.pages
+}
+
+// sized returns the capacity and object size function of a policy that
+// accounts capacity in bytes: the byte budget and trace sizes with -bytes,
+// or the number of pages and unit sizes without.
+func (cfg config) sized() (int, cache.SizeFunc[string, int]) {
+	if cfg.byteMode {
+		return cfg.size, objectSize
+	}
+	return cfg.pages, unitSize
+}
+
+// A policy builds a fresh cache for a simulation run.
+type policy func(cfg config) simCache
+
+// policies maps the names accepted by -policy to their constructors.
+var policies = map[string]policy{
+	"arc": func(cfg config) simCache {
+		arc := cache.NewARC[string, int](cfg.pageLimit(), cfg.pages)
+		if cfg.byteMode {
+			arc = cache.NewByteARC[string, int](cfg.size, objectSize)
+		}
+		if bits := int(cfg.param("arc.fingerprint")); bits > 0 {
+			arc.UseFingerprints(bits, int64(cfg.param("arc.seed")))
+		}
+		return arc
+	},
+	"lru": func(cfg config) simCache {
+		if cfg.byteMode {
+			return cache.NewByteLru[string, int](cfg.size, objectSize)
+		}
+		return cache.NewLru[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"fifo": func(cfg config) simCache {
+		return cache.NewFIFO[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"random": func(cfg config) simCache {
+		return cache.NewRandom[string, int](cfg.pageLimit(), cfg.pages, int64(cfg.param("random.seed")))
+	},
+	"mru": func(cfg config) simCache {
+		return cache.NewMRU[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"slru": func(cfg config) simCache {
+		return cache.NewSegmentedLRU[string, int](cfg.pageLimit(), cfg.pages, int(cfg.param("slru.segments")))
+	},
+	"clock": func(cfg config) simCache {
+		return cache.NewCLOCK[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"car": func(cfg config) simCache {
+		return cache.NewCAR[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"cart": func(cfg config) simCache {
+		return cache.NewCART[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"2q": func(cfg config) simCache {
+		return cache.NewTwoQ[string, int](cfg.pageLimit(), cfg.pages, cfg.pagesParam("2q.kin"), cfg.pagesParam("2q.kout"))
+	},
+	"2q-simple": func(cfg config) simCache {
+		return cache.NewSimpleTwoQ[string, int](cfg.pageLimit(), cfg.pages, cfg.pagesParam("2q.kin"))
+	},
+	"lirs": func(cfg config) simCache {
+		return cache.NewLIRS[string, int](cfg.pageLimit(), cfg.pages, cfg.pagesParam("lirs.hir"), cfg.pagesParam("lirs.ghosts"))
+	},
+	"sieve": func(cfg config) simCache {
+		return cache.NewSIEVE[string, int](cfg.pageLimit(), cfg.pages)
+	},
+	"s3fifo": func(cfg config) simCache {
+		return cache.NewS3FIFO[string, int](cfg.pageLimit(), cfg.pages, cfg.pagesParam("s3fifo.small"))
+	},
+	"wtinylfu": func(cfg config) simCache {
+		window := cfg.pagesParam("wtinylfu.window")
+		protected := int(cfg.param("wtinylfu.protected") * float64(cfg.pages-window))
+		return cache.NewWTinyLFU[string, int](cfg.pageLimit(), cfg.pages, window, protected, int64(cfg.param("wtinylfu.seed")))
+	},
+	"lfu": func(cfg config) simCache {
+		return cache.NewLFU[string, int](cfg.pageLimit(), cfg.pages, cfg.pagesParam("lfu.aging"))
+	},
+	"lruk": func(cfg config) simCache {
+		k, crp := int(cfg.param("lruk.k")), int(cfg.param("lruk.crp"))
+		return cache.NewLRUK[string, int](cfg.pageLimit(), cfg.pages, k, crp, cfg.pagesParam("lruk.history"))
+	},
+	"mq": func(cfg config) simCache {
+		queues := int(cfg.param("mq.queues"))
+		return cache.NewMQ[string, int](cfg.pageLimit(), cfg.pages, queues, cfg.pagesParam("mq.lifetime"), cfg.pagesParam("mq.qout"))
+	},
+	"lecar": func(cfg config) simCache {
+		return cache.NewLeCaR[string, int](cfg.pageLimit(), cfg.pages, cfg.param("lecar.rate"), int64(cfg.param("lecar.seed")))
+	},
+	"cacheus": func(cfg config) simCache {
+		return cache.NewCACHEUS[string, int](cfg.pageLimit(), cfg.pages, int64(cfg.param("cacheus.seed")))
+	},
+	"gdsf": func(cfg config) simCache {
+		limit, size := cfg.sized()
+		return cache.NewGDSF(limit, size, cache.UniformCost[string, int])
+	},
+	"gdsf-size": func(cfg config) simCache {
+		limit, size := cfg.sized()
+		return cache.NewGDSF(limit, size, cache.SizeCost[string, int])
+	},
+	"gdsf-latency": func(cfg config) simCache {
+		limit, size := cfg.sized()
+		cost := cache.LatencyCost[string, int](cfg.param("gdsf.rtt"), cfg.param("gdsf.bandwidth"))
+		return cache.NewGDSF(limit, size, cost)
+	},
+	"lhd": func(cfg config) simCache {
+		limit, size := cfg.sized()
+		return cache.NewLHD(limit, size, int64(cfg.param("lhd.seed")))
+	},
+	"opt": func(cfg config) simCache {
+		if cfg.byteMode {
+			return cache.NewByteOPT[string, int](cfg.size, objectSize, cfg.future)
+		}
+		return cache.NewOPT[string, int](cfg.pages, cfg.future)
+	},
+}
+
+// bytePolicies lists the policies that can account capacity in bytes.
+var bytePolicies = map[string]bool{
+	"arc":          true,
+	"gdsf":         true,
+	"gdsf-size":    true,
+	"gdsf-latency": true,
+	"lhd":          true,
+	"lru":          true,
+	"opt":          true,
+}
+
+// paramsRead lists the parameters each policy reads, which its records
+// hold.
+var paramsRead = map[string][]string{
+	"2q":           {"2q.kin", "2q.kout"},
+	"2q-simple":    {"2q.kin"},
+	"arc":          {"arc.fingerprint", "arc.seed"},
+	"cacheus":      {"cacheus.seed"},
+	"car":          {},
+	"cart":         {},
+	"clock":        {},
+	"fifo":         {},
+	"gdsf":         {},
+	"gdsf-latency": {"gdsf.bandwidth", "gdsf.rtt"},
+	"gdsf-size":    {},
+	"lecar":        {"lecar.rate", "lecar.seed"},
+	"lfu":          {"lfu.aging"},
+	"lhd":          {"lhd.seed"},
+	"lirs":         {"lirs.ghosts", "lirs.hir"},
+	"lru":          {},
+	"lruk":         {"lruk.crp", "lruk.history", "lruk.k"},
+	"mq":           {"mq.lifetime", "mq.qout", "mq.queues"},
+	"mru":          {},
+	"opt":          {},
+	"random":       {"random.seed"},
+	"s3fifo":       {"s3fifo.small"},
+	"sieve":        {},
+	"slru":         {"slru.segments"},
+	"wtinylfu":     {"wtinylfu.protected", "wtinylfu.seed", "wtinylfu.window"},
+}
+
+// offline lists the policies that have to read the whole trace up front.
+var offline = map[string]bool{
+	"opt": true,
+}
+
+// needsFuture reports whether any of the named policies is offline.
+func needsFuture(names []string) bool {
+	for _, name := range names {
+		if offline[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// policyNames returns the registered policy names in sorted order.
+func policyNames() []string {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsePolicies splits a comma separated list of policy names, rejecting
+// any name that is not registered.
+func parsePolicies(list string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := policies[name]; !ok {
+			return nil, fmt.Errorf("unknown policy %q (available: %s)", name, strings.Join(policyNames(), ", "))
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no policies given")
+	}
+	return names, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Checks that every policy holds objects with keys longer than -size/-pages
+// bytes in page mode
+func TestPageModeLongKeys(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&b, "%d,user_profile_cache_key_%010d,10,90,1,get,0\n", i, i%10)
+	}
+	path := filepath.Join(t.TempDir(), "twitter.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := source{path: path}
+
+	cfg := config{size: 1024, pages: 64, params: paramList{}}
+	if cfg.future, _ = scanKeys(src); len(cfg.future) != 1000 {
+		t.Fatalf("read %d keys. Should be: 1000", len(cfg.future))
+	}
+	for _, name := range policyNames() {
+		r := &run{name: name, cache: policies[name](cfg)}
+		if err := replay(src, []*run{r}, phases{}); err != nil {
+			t.Fatal(err)
+		}
+		if r.metrics.Hits < 900 {
+			t.Errorf("%s hit %d of 1000 requests for 10 long keys. Should be: at least 900", name, r.metrics.Hits)
+		}
+	}
+}
+
+// Checks that every policy lists the parameters it reads, and that every
+// parameter is read by some policy
+func TestParamsRead(t *testing.T) {
+	read := map[string]bool{}
+	for _, name := range policyNames() {
+		list, ok := paramsRead[name]
+		if !ok {
+			t.Errorf("%s does not list the parameters it reads", name)
+		}
+		for _, param := range list {
+			if _, ok := params[param]; !ok {
+				t.Errorf("%s reads the undeclared parameter %s", name, param)
+			}
+			read[param] = true
+		}
+	}
+	for _, param := range paramNames() {
+		if !read[param] {
+			t.Errorf("no policy reads the parameter %s", param)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// progress periodically reports how many requests have been simulated and
+// how fast, for long runs. It is safe to count from several goroutines.
+type progress struct {
+	out   io.Writer
+	count atomic.Int64
+	start time.Time
+	done  chan struct{}
+	exit  chan struct{}
+}
+
+// startProgress reports to out every interval until stop is called.
+func startProgress(out io.Writer, interval time.Duration) *progress {
+	p := &progress{
+		out:   out,
+		start: time.Now(),
+		done:  make(chan struct{}),
+		exit:  make(chan struct{}),
+	}
+	go func() {
+		defer close(p.exit)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.print("\r")
+			case <-p.done:
+				p.print("\r")
+				fmt.Fprintln(p.out)
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// add counts one simulated request. A nil progress counts nothing.
+func (p *progress) add() {
+	if p != nil {
+		p.count.Add(1)
+	}
+}
+
+// stop prints the final count and waits for the reporter to finish.
+func (p *progress) stop() {
+	if p == nil {
+		return
+	}
+	close(p.done)
+	<-p.exit
+}
+
+func (p *progress) print(prefix string) {
+	n := p.count.Load()
+	elapsed := time.Since(p.start)
+	rate := float64(n) / elapsed.Seconds()
+	fmt.Fprintf(p.out, "%s%d requests in %s (%.0f req/s)", prefix, n, elapsed.Round(100*time.Millisecond), rate)
+}
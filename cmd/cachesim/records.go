@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+// A record is the result of one policy over one trace, with what is needed
+// to merge and compare it with the results of other runs.
+type record struct {
+	Policy   string             `json:"policy"`
+	Params   map[string]float64 `json:"params"` // Every parameter of the policy, set or default
+	Size     int                `json:"size"`
+	Pages    int                `json:"pages"`
+	ByteMode bool               `json:"byte_mode"`
+	Trace    string             `json:"trace"`
+	Checksum string             `json:"checksum"` // SHA-256 of the trace file as stored
+
+	Format     string  `json:"format"`      // Trace format, as given or detected
+	Columns    string  `json:"columns"`     // Columns of a CSV trace, as given to -columns; "" for other formats
+	Header     bool    `json:"header"`      // Whether a CSV trace's header was skipped
+	BlockSize  int     `json:"block_size"`  // Bytes per block of a block trace, 0 for the format's default
+	Warmup     int     `json:"warmup"`      // Requests left out of the results
+	WarmupTime float64 `json:"warmup_time"` // Seconds of trace time left out of the results
+
+	Requests     int     `json:"requests"`
+	Hits         int     `json:"hits"`
+	Misses       int     `json:"misses"`
+	HitRatio     float64 `json:"hit_ratio"`
+	ByteHitRatio float64 `json:"byte_hit_ratio"`
+	OriginBytes  int64   `json:"origin_bytes"`
+	WrittenBytes int64   `json:"written_bytes"`
+	WallSeconds  float64 `json:"wall_seconds"` // Time to replay the trace against every policy
+	NsPerOp      float64 `json:"ns_per_op"`
+}
+
+// policyParams returns the value of every parameter the named policy reads.
+func (cfg config) policyParams(name string) map[string]float64 {
+	values := map[string]float64{}
+	for _, param := range paramsRead[name] {
+		values[param] = cfg.param(param)
+	}
+	return values
+}
+
+// checksum returns the SHA-256 of the trace file, or "" for standard input,
+// which can only be read once.
+func checksum(path string) (string, error) {
+	if path == trace.Stdin {
+		return "", nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// records returns a record of each run over the trace, replayed in phases,
+// which took wall time to replay. The trace's format is the one read, if
+// the source kept it.
+func records(runs []*run, cfg config, src source, ph phases, wall time.Duration) ([]record, error) {
+	sum, err := checksum(src.path)
+	if err != nil {
+		return nil, err
+	}
+	format := src.opts.Format
+	if src.format != nil {
+		format = *src.format
+	}
+	var columns string
+	var header bool
+	if format == trace.CSV {
+		columns, header = formatColumns(src.opts.Columns), src.opts.Columns.Header
+	}
+	recs := make([]record, len(runs))
+	for i, r := range runs {
+		m := &r.metrics
+		recs[i] = record{
+			Policy:       r.name,
+			Params:       cfg.policyParams(r.name),
+			Size:         cfg.size,
+			Pages:        cfg.pages,
+			ByteMode:     cfg.byteMode,
+			Trace:        filepath.Base(src.path),
+			Checksum:     sum,
+			Format:       format.String(),
+			Columns:      columns,
+			Header:       header,
+			BlockSize:    src.opts.BlockSize,
+			Warmup:       ph.warmup,
+			WarmupTime:   ph.warmupTime,
+			Requests:     m.Requests,
+			Hits:         m.Hits,
+			Misses:       m.Misses,
+			HitRatio:     m.HitRatio(),
+			ByteHitRatio: m.ByteHitRatio(),
+			OriginBytes:  m.OriginBytes,
+			WrittenBytes: m.WrittenBytes,
+			WallSeconds:  wall.Seconds(),
+			NsPerOp:      m.NsPerOp(),
+		}
+	}
+	return recs, nil
+}
+
+// writeRecords writes the records as JSON or CSV. In CSV the parameters of
+// a record are one column of name=value pairs.
+func writeRecords(out io.Writer, format string, recs []record) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(recs)
+	case "csv":
+		w := csv.NewWriter(out)
+		w.Write([]string{"policy", "params", "size", "pages", "byte_mode", "trace", "checksum", "format", "columns", "header", "block_size", "warmup", "warmup_time", "requests", "hits", "misses", "hit_ratio", "byte_hit_ratio", "origin_bytes", "written_bytes", "wall_seconds", "ns_per_op"})
+		for _, rec := range recs {
+			w.Write([]string{
+				rec.Policy,
+				paramList(rec.Params).String(),
+				strconv.Itoa(rec.Size),
+				strconv.Itoa(rec.Pages),
+				strconv.FormatBool(rec.ByteMode),
+				rec.Trace,
+				rec.Checksum,
+				rec.Format,
+				rec.Columns,
+				strconv.FormatBool(rec.Header),
+				strconv.Itoa(rec.BlockSize),
+				strconv.Itoa(rec.Warmup),
+				strconv.FormatFloat(rec.WarmupTime, 'f', -1, 64),
+				strconv.Itoa(rec.Requests),
+				strconv.Itoa(rec.Hits),
+				strconv.Itoa(rec.Misses),
+				strconv.FormatFloat(rec.HitRatio, 'f', 6, 64),
+				strconv.FormatFloat(rec.ByteHitRatio, 'f', 6, 64),
+				strconv.FormatInt(rec.OriginBytes, 10),
+				strconv.FormatInt(rec.WrittenBytes, 10),
+				strconv.FormatFloat(rec.WallSeconds, 'f', 6, 64),
+				strconv.FormatFloat(rec.NsPerOp, 'f', 1, 64),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	return fmt.Errorf("unknown output %q (available: table, csv, json)", format)
+}
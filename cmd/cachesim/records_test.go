@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+// Checks that records carry the policy's parameters, the trace's checksum
+// and how it was read and replayed, with the detected format and the
+// columns of CSV traces only, and round trip through JSON and CSV
+func TestRecords(t *testing.T) {
+	src := writeTrace(t, 100, 10)
+	src.format = new(trace.Format)
+	src.opts.Columns = trace.Columns{Time: -1, Key: 0, Size: 2}
+	src.opts.BlockSize = 4096
+	cfg := config{size: 100, pages: 10, params: paramList{"2q.kin": 0.5}}
+	runs := []*run{
+		{name: "2q-simple", cache: policies["2q-simple"](cfg)},
+		{name: "lru", cache: policies["lru"](cfg)},
+	}
+	ph := phases{warmup: 10}
+	if err := replay(src, runs, ph); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := records(runs, cfg, src, ph, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"2q.kin": 0.5}
+	if !reflect.DeepEqual(recs[0].Params, want) || len(recs[1].Params) != 0 {
+		t.Errorf("records have parameters %v and %v. Should be: %v and none", recs[0].Params, recs[1].Params, want)
+	}
+	if len(recs[0].Checksum) != 64 || recs[0].Checksum != recs[1].Checksum || recs[0].Trace != "trace.txt" {
+		t.Errorf("records have trace %q and checksum %q", recs[0].Trace, recs[0].Checksum)
+	}
+	if recs[1].Requests != 90 || recs[1].Hits != 90 || recs[1].WallSeconds != 1 {
+		t.Errorf("lru record has %d requests, %d hits and %g seconds. Should be: 90, 90 and 1", recs[1].Requests, recs[1].Hits, recs[1].WallSeconds)
+	}
+	if r := recs[1]; r.Format != "webcachesim" || r.Columns != "" || r.BlockSize != 4096 || r.Warmup != 10 {
+		t.Errorf("lru record has format %q, columns %q, block size %d and warm-up %d", r.Format, r.Columns, r.BlockSize, r.Warmup)
+	}
+
+	// The same trace read as CSV records its columns
+	csvSrc := src
+	csvSrc.opts.Format = trace.CSV
+	csvSrc.opts.Columns = trace.Columns{Time: -1, Key: 1, Size: 2, Comma: ' ', Header: true}
+	if err := replay(csvSrc, runs[1:], phases{}); err != nil {
+		t.Fatal(err)
+	}
+	csvRecs, err := records(runs[1:], cfg, csvSrc, phases{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := csvRecs[0]; r.Format != "csv" || r.Columns != "key=1,size=2" || !r.Header {
+		t.Errorf("CSV record has format %q, columns %q and header %v. Should be: csv, key=1,size=2 and true", r.Format, r.Columns, r.Header)
+	}
+
+	var out bytes.Buffer
+	if err := writeRecords(&out, "json", recs); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []record
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, recs) {
+		t.Errorf("JSON records do not round trip: %v", err)
+	}
+
+	out.Reset()
+	if err := writeRecords(&out, "csv", recs); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 3 || rows[1][1] != "2q.kin=0.5" || rows[2][0] != "lru" || rows[2][7] != "webcachesim" || rows[2][8] != "" {
+		t.Errorf("CSV records are %v: %v", rows, err)
+	}
+
+	if err := writeRecords(&out, "xml", recs); err == nil {
+		t.Errorf("writeRecords should have failed on an unknown format")
+	}
+}
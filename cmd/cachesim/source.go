@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+// A source is a trace file and how to read it. The trace is streamed, so
+// memory use does not depend on its length.
+type source struct {
+	path     string // trace.Stdin reads standard input
+	opts     trace.Options
+	progress *progress     // Counts every request read, if not nil
+	timed    bool          // Whether the trace has to have request times
+	format   *trace.Format // Set to the format read, detected or not, if not nil
+}
+
+// each calls fn for every request in the trace.
+func (src source) each(fn func(req trace.Request)) error {
+	tr, err := trace.Open(src.path, src.opts)
+	if err != nil {
+		return err
+	}
+	defer tr.Close()
+	if src.format != nil {
+		*src.format = tr.Format()
+	}
+	if src.timed && !tr.Timed() {
+		return fmt.Errorf("%s: %s traces have no request times for -warmup-time or -window-time", src.path, tr.Format())
+	}
+
+	for {
+		req, err := tr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", src.path, err)
+		}
+		fn(req)
+		src.progress.add()
+	}
+}
+
+// parseColumns reads CSV columns given as "time=0,key=1,size=2". Fields
+// that are left out are absent from the trace.
+func parseColumns(spec string) (trace.Columns, error) {
+	cols := trace.Columns{Time: -1, Key: -1, Size: -1}
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		index, err := strconv.Atoi(value)
+		if !ok || err != nil || index < 0 {
+			return cols, fmt.Errorf("columns %q: expected field=index, got %q", spec, part)
+		}
+		switch strings.ToLower(name) {
+		case "time":
+			cols.Time = index
+		case "key":
+			cols.Key = index
+		case "size":
+			cols.Size = index
+		default:
+			return cols, fmt.Errorf("columns %q: unknown field %q (available: time, key, size)", spec, name)
+		}
+	}
+	if cols.Key < 0 {
+		return cols, fmt.Errorf("columns %q: no key column", spec)
+	}
+	return cols, nil
+}
+
+// formatColumns writes CSV columns the way parseColumns reads them.
+func formatColumns(cols trace.Columns) string {
+	var parts []string
+	for _, field := range []struct {
+		name  string
+		index int
+	}{{"time", cols.Time}, {"key", cols.Key}, {"size", cols.Size}} {
+		if field.index >= 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", field.name, field.index))
+		}
+	}
+	return strings.Join(parts, ",")
+}
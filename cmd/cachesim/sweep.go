@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	cache "github.com/gleising/COS_Final_Project"
+	"github.com/gleising/COS_Final_Project/trace"
+)
+
+// A point is the miss ratio of one policy at one cache capacity.
+type point struct {
+	Policy        string  `json:"policy"`
+	Capacity      int     `json:"capacity"`
+	Requests      int     `json:"requests"`
+	Misses        int     `json:"misses"`
+	MissRatio     float64 `json:"miss_ratio"`
+	ByteMissRatio float64 `json:"byte_miss_ratio"`
+}
+
+// parseSweep turns "min:max:steps" into the capacities to simulate, spaced
+// linearly or logarithmically. Duplicates from rounding are dropped.
+func parseSweep(spec string, logScale bool) ([]int, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("sweep %q: expected min:max:steps", spec)
+	}
+	var vals [3]int
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("sweep %q: %v", spec, err)
+		}
+		vals[i] = v
+	}
+	lo, hi, steps := vals[0], vals[1], vals[2]
+	if lo <= 0 || hi < lo || steps <= 0 {
+		return nil, fmt.Errorf("sweep %q: need 0 < min <= max and steps > 0", spec)
+	}
+
+	var capacities []int
+	for i := 0; i < steps; i++ {
+		frac := 0.0
+		if steps > 1 {
+			frac = float64(i) / float64(steps-1)
+		}
+		var c float64
+		if logScale {
+			c = float64(lo) * math.Pow(float64(hi)/float64(lo), frac)
+		} else {
+			c = float64(lo) + frac*float64(hi-lo)
+		}
+		capacity := int(math.Round(c))
+		if len(capacities) == 0 || capacity != capacities[len(capacities)-1] {
+			capacities = append(capacities, capacity)
+		}
+	}
+	return capacities, nil
+}
+
+// withCapacity returns cfg resized to capacity pages, or bytes in byte mode.
+func (cfg config) withCapacity(capacity int) config {
+	if cfg.byteMode {
+		cfg.size = capacity
+		return cfg
+	}
+	cfg.pages = capacity
+	return cfg
+}
+
+// sweep replays the trace for every policy at every capacity, running the
+// simulations in parallel. Each simulation streams the trace on its own.
+// With a positive stackRate, the LRU curve is instead computed in a single
+// pass from stack distances, sampling that fraction of the keys. Requests
+// in the warm-up of ph are left out of every point.
+func sweep(src source, names []string, cfg config, capacities []int, stackRate float64, ph phases) ([]point, error) {
+	type job struct {
+		index    int
+		name     string
+		capacity int
+	}
+
+	points := make([]point, len(names)*len(capacities))
+	errs := make([]error, len(points))
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	useStack := func(name string) bool {
+		return name == "lru" && stackRate > 0
+	}
+	for i, name := range names {
+		if !useStack(name) {
+			continue
+		}
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			errs[index] = stackCurve(src, capacities, stackRate, points[index:index+len(capacities)])
+		}(i * len(capacities))
+	}
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				r := &run{name: j.name, cache: policies[j.name](cfg.withCapacity(j.capacity))}
+				if errs[j.index] = replay(src, []*run{r}, ph); errs[j.index] != nil {
+					continue
+				}
+				m := &r.metrics
+				points[j.index] = point{
+					Policy:        j.name,
+					Capacity:      j.capacity,
+					Requests:      m.Requests,
+					Misses:        m.Misses,
+					MissRatio:     1 - m.HitRatio(),
+					ByteMissRatio: 1 - m.ByteHitRatio(),
+				}
+			}
+		}()
+	}
+
+	for i, name := range names {
+		if useStack(name) {
+			continue
+		}
+		for k, capacity := range capacities {
+			jobs <- job{index: i*len(capacities) + k, name: name, capacity: capacity}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+// stackCurve fills points with the LRU miss ratio at each capacity, found in
+// one pass over the trace. Capacities are in pages, as every object takes up
+// one page of the LRU stack.
+func stackCurve(src source, capacities []int, rate float64, points []point) error {
+	sd := cache.NewSampledStackDistance[string](rate)
+	requests := 0
+	err := src.each(func(req trace.Request) {
+		requests++
+		sd.Access(req.Key, req.Size)
+	})
+	if err != nil {
+		return err
+	}
+	for i, capacity := range capacities {
+		missRatio := sd.MissRatio(capacity)
+		points[i] = point{
+			Policy:        "lru",
+			Capacity:      capacity,
+			Requests:      requests,
+			Misses:        int(math.Round(missRatio * float64(requests))),
+			MissRatio:     missRatio,
+			ByteMissRatio: sd.ByteMissRatio(capacity),
+		}
+	}
+	return nil
+}
+
+// writeCurve writes the miss ratio curve as CSV or JSON.
+func writeCurve(out io.Writer, format string, points []point) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(points)
+	case "csv":
+		w := csv.NewWriter(out)
+		w.Write([]string{"policy", "capacity", "requests", "misses", "miss_ratio", "byte_miss_ratio"})
+		for _, p := range points {
+			w.Write([]string{
+				p.Policy,
+				strconv.Itoa(p.Capacity),
+				strconv.Itoa(p.Requests),
+				strconv.Itoa(p.Misses),
+				strconv.FormatFloat(p.MissRatio, 'f', 6, 64),
+				strconv.FormatFloat(p.ByteMissRatio, 'f', 6, 64),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	return fmt.Errorf("unknown format %q (available: csv, json)", format)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Checks linear and logarithmic spacing of sweep capacities
+func TestParseSweep(t *testing.T) {
+	tests := []struct {
+		spec string
+		log  bool
+		want []int
+	}{
+		{"10:50:5", false, []int{10, 20, 30, 40, 50}},
+		{"1:1000:4", true, []int{1, 10, 100, 1000}},
+		{"1:2:5", false, []int{1, 2}},
+		{"7:7:1", true, []int{7}},
+	}
+	for _, test := range tests {
+		got, err := parseSweep(test.spec, test.log)
+		if err != nil {
+			t.Errorf("parseSweep(%q) failed: %v", test.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseSweep(%q) is %v. Should be: %v", test.spec, got, test.want)
+		}
+	}
+
+	for _, spec := range []string{"", "10:5:3", "0:10:3", "1:10", "a:b:c"} {
+		if _, err := parseSweep(spec, false); err == nil {
+			t.Errorf("parseSweep(%q) should have failed", spec)
+		}
+	}
+}
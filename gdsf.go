@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"container/heap"
+)
+
+// A CostFunc returns the cost of fetching a binding of the given size on a
+// miss, which GDSF weighs against the space the binding takes.
+type CostFunc[K comparable, V any] func(key K, value V, size int) float64
+
+// UniformCost counts every miss the same, so GDSF favours small bindings
+// and maximises the object hit ratio.
+func UniformCost[K comparable, V any](key K, value V, size int) float64 {
+	return 1
+}
+
+// SizeCost counts the bytes fetched on a miss, so GDSF maximises the byte
+// hit ratio.
+func SizeCost[K comparable, V any](key K, value V, size int) float64 {
+	return float64(size)
+}
+
+// LatencyCost counts the time to fetch a binding over a link with the given
+// round trip time in seconds and bandwidth in bytes per second.
+func LatencyCost[K comparable, V any](rtt float64, bandwidth float64) CostFunc[K, V] {
+	return func(key K, value V, size int) float64 {
+		return rtt + float64(size)/bandwidth
+	}
+}
+
+// A GDSF is the GreedyDual-Size-Frequency policy of Cherkasova (1998) for
+// bindings of different sizes and fetch costs. Each binding has a priority
+// of L + uses * cost / size and the lowest one is evicted. L is raised to
+// the priority of every evicted binding, so bindings that have not been
+// used since L was lower age out.
+type GDSF[K comparable, V any] struct {
+	limit    int // Total number of bytes in the cache
+	used     int
+	inflated float64 // L, the priority of the last evicted binding
+	size     SizeFunc[K, V]
+	cost     CostFunc[K, V]
+
+	entries map[K]*gdsfEntry[K, V]
+	queue   gdsfQueue[K, V]
+
+	hits   int
+	misses int
+}
+
+type gdsfEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	size     int
+	uses     int
+	priority float64
+	index    int // Position in the queue
+}
+
+// NewGDSF returns a GDSF holding limit bytes of bindings measured by size,
+// whose misses cost what cost returns. Bindings count as at least one byte,
+// so that ones of size 0 are evicted too. MaxPages and RemainingPages count
+// bytes.
+func NewGDSF[K comparable, V any](limit int, size SizeFunc[K, V], cost CostFunc[K, V]) *GDSF[K, V] {
+	return &GDSF[K, V]{
+		limit:   limit,
+		size:    atLeastOneByte(size),
+		cost:    cost,
+		entries: make(map[K]*gdsfEntry[K, V]),
+	}
+}
+
+func (gdsf *GDSF[K, V]) MaxPages() int {
+	return gdsf.limit
+}
+
+func (gdsf *GDSF[K, V]) RemainingPages() int {
+	return gdsf.limit - gdsf.used
+}
+
+// Bytes returns the total size of the bindings in the cache.
+func (gdsf *GDSF[K, V]) Bytes() int {
+	return gdsf.used
+}
+
+// Get returns the value of key, counting a use that raises its priority.
+func (gdsf *GDSF[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := gdsf.entries[key]
+	if !ok {
+		gdsf.misses += 1
+		return value, false
+	}
+	e.uses += 1
+	gdsf.prioritize(e)
+	heap.Fix(&gdsf.queue, e.index)
+	gdsf.hits += 1
+	return e.value, true
+}
+
+// Set adds the binding, evicting the bindings of lowest priority until it
+// fits. Setting a cached key replaces its value and keeps its uses. A
+// binding larger than the whole cache is rejected.
+func (gdsf *GDSF[K, V]) Set(key K, value V) bool {
+	size := gdsf.size(key, value)
+	if size > gdsf.limit {
+		return false
+	}
+	e, ok := gdsf.entries[key]
+	if ok {
+		heap.Remove(&gdsf.queue, e.index)
+		gdsf.used -= e.size
+	} else {
+		e = &gdsfEntry[K, V]{key: key, uses: 1}
+		gdsf.entries[key] = e
+	}
+	for gdsf.used+size > gdsf.limit {
+		victim := heap.Pop(&gdsf.queue).(*gdsfEntry[K, V])
+		gdsf.inflated = victim.priority
+		gdsf.used -= victim.size
+		delete(gdsf.entries, victim.key)
+	}
+	e.value = value
+	e.size = size
+	gdsf.prioritize(e)
+	heap.Push(&gdsf.queue, e)
+	gdsf.used += size
+	return true
+}
+
+// prioritize sets the priority of an entry from the current L.
+func (gdsf *GDSF[K, V]) prioritize(e *gdsfEntry[K, V]) {
+	e.priority = gdsf.inflated + float64(e.uses)*gdsf.cost(e.key, e.value, e.size)/float64(e.size)
+}
+
+func (gdsf *GDSF[K, V]) Len() int {
+	return len(gdsf.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (gdsf *GDSF[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   gdsf.hits,
+		Misses: gdsf.misses,
+	}
+}
+
+// gdsfQueue is a min-heap of entries ordered by priority.
+type gdsfQueue[K comparable, V any] []*gdsfEntry[K, V]
+
+func (q gdsfQueue[K, V]) Len() int           { return len(q) }
+func (q gdsfQueue[K, V]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q gdsfQueue[K, V]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *gdsfQueue[K, V]) Push(x any) {
+	e := x.(*gdsfEntry[K, V])
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *gdsfQueue[K, V]) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
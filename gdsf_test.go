@@ -0,0 +1,130 @@
+/******************************************************************************
+ * gdsf_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for gdsf.go, following the behaviours described
+ *    in the GreedyDual-Size-Frequency paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that with a uniform cost the binding of largest size is evicted
+func TestGDSFUniformCost(t *testing.T) {
+	gdsf := NewGDSF(100, valueSize, UniformCost[string, int])
+
+	gdsf.Set("a", 50)
+	gdsf.Set("b", 10)
+	gdsf.Set("c", 10)
+	gdsf.Set("d", 30)
+	gdsf.Set("e", 10)
+
+	if _, ok := gdsf.entries["a"]; ok {
+		t.Errorf("Failed to evict the largest binding. Key is: %s", "a")
+		t.FailNow()
+	}
+	if gdsf.Bytes() != 60 || gdsf.inflated != 1.0/50 {
+		t.Errorf("Failed to account the eviction. Bytes are: %d, L is: %g", gdsf.Bytes(), gdsf.inflated)
+		t.FailNow()
+	}
+	if gdsf.Set("f", 101) {
+		t.Errorf("Admitted a binding larger than the cache. Size is: %d", 101)
+		t.FailNow()
+	}
+}
+
+// Checks that with a size cost the least frequently used binding is evicted,
+// whatever its size
+func TestGDSFSizeCost(t *testing.T) {
+	gdsf := NewGDSF(100, valueSize, SizeCost[string, int])
+
+	gdsf.Set("a", 50)
+	gdsf.Set("b", 30)
+	gdsf.Set("c", 20)
+	gdsf.Get("a")
+	gdsf.Get("c")
+	gdsf.Set("d", 20)
+
+	if _, ok := gdsf.entries["b"]; ok {
+		t.Errorf("Failed to evict the least frequently used binding. Key is: %s", "b")
+		t.FailNow()
+	}
+	if p := gdsf.entries["d"].priority; p != 2 {
+		t.Errorf("Failed to inflate the priority of a new binding. Priority is: %g when it should be %g", p, 2.0)
+		t.FailNow()
+	}
+}
+
+// Checks that a binding used often long ago is eventually evicted as L rises
+func TestGDSFAging(t *testing.T) {
+	gdsf := NewGDSF(3, func(string, int) int { return 1 }, UniformCost[string, int])
+
+	gdsf.Set("a", 1)
+	for i := 0; i < 3; i++ {
+		gdsf.Get("a")
+	}
+	for i := 0; i < 2; i++ {
+		gdsf.Set(fmt.Sprintf("key%d", i), 1)
+	}
+	if _, ok := gdsf.entries["a"]; !ok {
+		t.Errorf("Evicted the most frequently used binding. Key is: %s", "a")
+		t.FailNow()
+	}
+	for i := 2; i < 20; i++ {
+		gdsf.Set(fmt.Sprintf("key%d", i), 1)
+	}
+	if _, ok := gdsf.entries["a"]; ok {
+		t.Errorf("Failed to age out a binding no longer used. Priority is: %g, L is: %g", gdsf.entries["a"].priority, gdsf.inflated)
+		t.FailNow()
+	}
+}
+
+// Checks the cost of fetching bindings over a link
+func TestGDSFLatencyCost(t *testing.T) {
+	cost := LatencyCost[string, int](0.5, 100)
+
+	if got := cost("a", 0, 50); got != 1 {
+		t.Errorf("Wrong latency. Cost is: %g when it should be %g", got, 1.0)
+		t.FailNow()
+	}
+
+	// The round trip dominates small bindings, so large ones are evicted
+	gdsf := NewGDSF(100, valueSize, cost)
+	gdsf.Set("a", 10)
+	gdsf.Set("b", 80)
+	gdsf.Set("c", 20)
+	if _, ok := gdsf.entries["b"]; ok || gdsf.Len() != 2 {
+		t.Errorf("Failed to evict the binding of lowest cost per byte. Length is: %d", gdsf.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that bindings of size 0 count as one byte, so they are evicted
+func TestGDSFZeroSize(t *testing.T) {
+	gdsf := NewGDSF(10, valueSize, UniformCost[string, int])
+
+	for i := 0; i < 100; i++ {
+		gdsf.Set(fmt.Sprintf("key%d", i), 0)
+	}
+	if gdsf.Len() != 10 || gdsf.Bytes() != 10 {
+		t.Errorf("Failed to bound bindings of size 0. Length is: %d and bytes are: %d", gdsf.Len(), gdsf.Bytes())
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkGDSF(b *testing.B) {
+	benchHits(b, NewGDSF(64, func(int, int) int { return 1 }, UniformCost[int, int]))
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"math"
+)
+
+// A ghostList is one of ARC's ghost lists B1 and B2: an LRU of the keys
+// evicted from T1 or T2 and their sizes, which may remember keys exactly or
+// only by a fingerprint.
+type ghostList[K comparable] interface {
+	Contains(key K) bool
+	Set(key K, size int) bool
+	Remove(key K) (size int, ok bool)
+	RemoveLRU()
+	Len() int
+	Bytes() int
+}
+
+// keyGhosts is a ghost list that remembers every key exactly.
+type keyGhosts[K comparable] struct {
+	keys *LRU[K, int]
+}
+
+func (g keyGhosts[K]) Contains(key K) bool              { return g.keys.Contains(key) }
+func (g keyGhosts[K]) Set(key K, size int) bool         { return g.keys.Set(key, size) }
+func (g keyGhosts[K]) Remove(key K) (size int, ok bool) { return g.keys.Remove(key) }
+func (g keyGhosts[K]) RemoveLRU()                       { g.keys.RemoveLRU() }
+func (g keyGhosts[K]) Len() int                         { return g.keys.Len() }
+func (g keyGhosts[K]) Bytes() int                       { return g.keys.Bytes() }
+
+// fingerprintGhosts is a ghost list that remembers keys by a fingerprint of
+// a few bits of their hash, so it takes the same space whatever the keys.
+// Keys with the same fingerprint are mistaken for each other.
+type fingerprintGhosts[K comparable] struct {
+	seed   int64
+	mask   uint64
+	hashes *LRU[uint64, int]
+}
+
+// fingerprint returns the low bits of the key's hash.
+func (g fingerprintGhosts[K]) fingerprint(key K) uint64 {
+	return seededHash(g.seed, key) & g.mask
+}
+
+func (g fingerprintGhosts[K]) Contains(key K) bool {
+	return g.hashes.Contains(g.fingerprint(key))
+}
+
+func (g fingerprintGhosts[K]) Set(key K, size int) bool {
+	return g.hashes.Set(g.fingerprint(key), size)
+}
+
+func (g fingerprintGhosts[K]) Remove(key K) (size int, ok bool) {
+	return g.hashes.Remove(g.fingerprint(key))
+}
+
+func (g fingerprintGhosts[K]) RemoveLRU() { g.hashes.RemoveLRU() }
+func (g fingerprintGhosts[K]) Len() int   { return g.hashes.Len() }
+func (g fingerprintGhosts[K]) Bytes() int { return g.hashes.Bytes() }
+
+// GhostStats counts how often a requested key was found in ARC's ghost
+// lists. With fingerprints some of those hits are keys mistaken for others;
+// FalsePositives is an upper bound on the number expected, since it also
+// counts the chance of a mistake on lookups of keys that are ghosts.
+type GhostStats struct {
+	B1Hits         int
+	B2Hits         int
+	FalsePositives float64
+}
+
+// UseFingerprints makes an empty ARC remember the keys in its ghost lists
+// by fingerprints of bits bits, from 1 to 64, hashed with the given seed,
+// instead of the keys themselves. This bounds the memory of each ghost
+// whatever the size of the keys, at the cost of ghost hits on keys that
+// were never evicted: with n ghosts, a key that is not one of them is found
+// with a chance of at most about n/2^bits.
+func (arc *ARC[K, V]) UseFingerprints(bits int, seed int64) {
+	bits = min(max(bits, 1), 64)
+	arc.fingerprint_bits = bits
+	mask := uint64(math.MaxUint64) >> (64 - bits)
+	if arc.byte_mode {
+		arc.b1 = fingerprintGhosts[K]{seed, mask, NewByteLru[uint64, int](arc.num_bytes, ghostSize[uint64])}
+		arc.b2 = fingerprintGhosts[K]{seed, mask, NewByteLru[uint64, int](2*arc.num_bytes, ghostSize[uint64])}
+		return
+	}
+	arc.b1 = fingerprintGhosts[K]{seed, mask, newHistory[uint64, int](arc.num_pages)}
+	arc.b2 = fingerprintGhosts[K]{seed, mask, newHistory[uint64, int](arc.num_pages)}
+}
+
+// probeGhosts counts a ghost lookup of a key that is not cached, adding
+// the chance that it matched some ghost's fingerprint by accident to the
+// estimate of false positives. The key is not known to be a ghost or not,
+// so every ghost is counted and the estimate is an upper bound.
+func (arc *ARC[K, V]) probeGhosts() {
+	if arc.fingerprint_bits > 0 {
+		ghosts := float64(arc.b1.Len() + arc.b2.Len())
+		arc.false_positives += ghosts / math.Exp2(float64(arc.fingerprint_bits))
+	}
+}
+
+// GhostStats returns the hits in the ghost lists B1 and B2.
+func (arc *ARC[K, V]) GhostStats() *GhostStats {
+	return &GhostStats{
+		B1Hits:         arc.b1_hits,
+		B2Hits:         arc.b2_hits,
+		FalsePositives: arc.false_positives,
+	}
+}
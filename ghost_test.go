@@ -0,0 +1,134 @@
+/******************************************************************************
+ * ghost_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for ghost.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// Replays a trace against an ARC of 100 pages, fingerprinting its ghosts
+// with bits bits hashed with seed if bits is not 0
+func replayARC(t *testing.T, name string, bits int, seed int64) *ARC[string, int] {
+	arc := NewARC[string, int](1000, 100)
+	if bits > 0 {
+		arc.UseFingerprints(bits, seed)
+	}
+	for _, key := range readKeys(t, name) {
+		if _, ok := arc.Get(key); !ok {
+			arc.Set(key, 0)
+		}
+	}
+	return arc
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that ghost hits are counted by list
+func TestARCGhostStats(t *testing.T) {
+	arc := NewARC[string, []byte](limit, pages)
+
+	addCache(arc, 1, 8)
+	arc.Get("key1")
+	addCache(arc, 9, 9) // key2 moves to B1
+	addCache(arc, 2, 2)
+	stats := arc.GhostStats()
+	if stats.B1Hits != 1 || stats.B2Hits != 0 || stats.FalsePositives != 0 {
+		t.Errorf("Miscounted ghost hits. Stats are: %+v", *stats)
+		t.FailNow()
+	}
+}
+
+// Checks that 64-bit fingerprints behave like exact keys on a trace, and
+// that short ones find more ghosts and expect false positives
+func TestARCFingerprints(t *testing.T) {
+	exact := replayARC(t, "trace1.txt", 0, 1)
+	wide := replayARC(t, "trace1.txt", 64, 1)
+	if wide.hits != exact.hits || *wide.GhostStats() != (GhostStats{exact.b1_hits, exact.b2_hits, wide.false_positives}) {
+		t.Errorf("Changed the results with 64-bit fingerprints. Hits are: %d when they should be %d", wide.hits, exact.hits)
+		t.FailNow()
+	}
+	if wide.false_positives > 0.001 {
+		t.Errorf("Expected false positives of 64-bit fingerprints. Estimate is: %f", wide.false_positives)
+		t.FailNow()
+	}
+
+	narrow := replayARC(t, "trace1.txt", 8, 1)
+	ghostHits := func(arc *ARC[string, int]) int { return arc.b1_hits + arc.b2_hits }
+	if ghostHits(narrow) <= ghostHits(exact) || narrow.false_positives < 1 {
+		t.Errorf("Failed to find false positives with 8-bit fingerprints. Ghost hits are: %d, estimate is: %f", ghostHits(narrow), narrow.false_positives)
+		t.FailNow()
+	}
+	if narrow.Len() > 100 || narrow.b1.Len()+narrow.b2.Len() > 100 {
+		t.Errorf("Broke the directory bounds. Ghosts are: %d", narrow.b1.Len()+narrow.b2.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that the same seed gives the same fingerprints, and so the same
+// results, in every run
+func TestARCFingerprintSeed(t *testing.T) {
+	first := replayARC(t, "trace1.txt", 8, 1)
+	again := replayARC(t, "trace1.txt", 8, 1)
+	other := replayARC(t, "trace1.txt", 8, 2)
+	if again.hits != first.hits || *again.GhostStats() != *first.GhostStats() {
+		t.Errorf("Changed the results with the same seed. Hits are: %d when they should be %d", again.hits, first.hits)
+		t.FailNow()
+	}
+	if *other.GhostStats() == *first.GhostStats() {
+		t.Errorf("Failed to change the fingerprints with another seed. Stats are: %+v", *other.GhostStats())
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+// Reports the heap bytes an ARC takes per cached binding with 128-byte keys,
+// remembering its ghosts by key and by fingerprint
+func BenchmarkARCFingerprintMemory(b *testing.B) {
+	const n = 1 << 12
+	prefix := strings.Repeat("k", 120)
+	for _, bits := range []int{0, 64} {
+		b.Run(fmt.Sprintf("bits=%d", bits), func(b *testing.B) {
+			var stats runtime.MemStats
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				before := stats.HeapAlloc
+
+				arc := NewARC[string, int](256*n, n)
+				if bits > 0 {
+					arc.UseFingerprints(bits, 1)
+				}
+				for key := 0; key < n; key++ {
+					arc.Set(fmt.Sprintf("%s%08d", prefix, key), key)
+					arc.Get(fmt.Sprintf("%s%08d", prefix, key))
+				}
+				for key := n; key < 3*n; key++ {
+					arc.Set(fmt.Sprintf("%s%08d", prefix, key), key)
+				}
+
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				b.ReportMetric(float64(stats.HeapAlloc-before)/float64(arc.Len()), "B/entry")
+				runtime.KeepAlive(arc)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/gleising/COS_Final_Project
 
-go 1.19
+go 1.24
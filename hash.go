@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+)
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// seededHash hashes the seed and the key with FNV-1a, so the same seed
+// gives the same hash in every run, unlike maphash. Strings and integers
+// are hashed by their bytes, and keys of other types as printed by %#v.
+func seededHash[K comparable](seed int64, key K) uint64 {
+	x := fnvUint64(fnvOffset, uint64(seed))
+	switch k := any(key).(type) {
+	case string:
+		x = fnvString(x, k)
+	case int:
+		x = fnvUint64(x, uint64(k))
+	case int64:
+		x = fnvUint64(x, uint64(k))
+	case uint64:
+		x = fnvUint64(x, k)
+	default:
+		x = fnvString(x, fmt.Sprintf("%#v", key))
+	}
+	// FNV's low bits mix poorly, so finish with the mixer of SplitMix64
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+func fnvString(x uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		x = (x ^ uint64(s[i])) * fnvPrime
+	}
+	return x
+}
+
+func fnvUint64(x uint64, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		x = (x ^ v>>(8*i)&0xff) * fnvPrime
+	}
+	return x
+}
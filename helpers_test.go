@@ -1,4 +1,4 @@
-package test
+package cache
 
 import (
 	"testing"
@@ -31,7 +31,7 @@ func bytesEqual(a []byte, b []byte) bool {
 	return true
 }
 
-func addKeys(arc *ARC, start int, end int){
+func addKeys(arc *ARC[string, []byte], start int, end int){
 	for i := start; i <= end; i++ {
 		key := fmt.Sprintf("key%d", i)
 		val := []byte(key)
@@ -39,7 +39,7 @@ func addKeys(arc *ARC, start int, end int){
 	}
 }
 
-func checkLengths(arc *ARC, t *testing.T, t1 int, t2 int, b1 int, b2 int){
+func checkLengths[V any](arc *ARC[string, V], t *testing.T, t1 int, t2 int, b1 int, b2 int){
 	if arc.t1.Len() != t1{
 		t.Errorf("Failed to not update LRU length. Length is: %d when it should be %d", arc.t1.Len(), t1)
 		t.FailNow()
@@ -56,4 +56,12 @@ func checkLengths(arc *ARC, t *testing.T, t1 int, t2 int, b1 int, b2 int){
 		t.Errorf("Failed to update B2 ghost cache length. Length is: %d when it should be %d", arc.b2.Len(), b2)
 		t.FailNow()
 	}
-}
\ No newline at end of file
+}
+
+func addCache(c Cache[string, []byte], start int, end int){
+	for i := start; i <= end; i++ {
+		key := fmt.Sprintf("key%d", i)
+		val := []byte(key)
+		c.Set(key, val)
+	}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"math"
+	"math/rand"
+)
+
+// experts holds the weights of two eviction policies, the experts, learned
+// from regret. Each expert keeps a history of the keys it chose to evict,
+// like ARC's ghost lists. A miss on a key in one expert's history means the
+// eviction was a mistake, so the other expert's weight grows, by more the
+// more recent the mistake. Evictions follow an expert chosen at random by
+// weight.
+type experts struct {
+	weight   [2]float64
+	rate     float64 // Learning rate
+	discount float64 // Weight of a mistake made one lookup earlier
+	rand     *rand.Rand
+}
+
+// newExperts returns equally weighted experts for a cache of pages bindings.
+// Mistakes are discounted so that one made pages lookups ago counts for
+// 0.005 of a fresh one, as in LeCaR.
+func newExperts(pages int, rate float64, seed int64) experts {
+	return experts{
+		weight:   [2]float64{0.5, 0.5},
+		rate:     rate,
+		discount: math.Pow(0.005, 1/float64(pages)),
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// regret records that the eviction expert made age lookups ago was a
+// mistake, rewarding the other expert.
+func (x *experts) regret(expert int, age int) {
+	x.weight[1-expert] *= math.Exp(x.rate * math.Pow(x.discount, float64(age)))
+	total := x.weight[0] + x.weight[1]
+	x.weight[0] /= total
+	x.weight[1] /= total
+}
+
+// choose returns the expert that decides the next eviction.
+func (x *experts) choose() int {
+	if x.rand.Float64() < x.weight[0] {
+		return 0
+	}
+	return 1
+}
+
+// The experts of a LeCaR.
+const (
+	lecarLRU = iota
+	lecarLFU
+)
+
+// A LeCaR is the Learning Cache Replacement policy of Vietri et al.
+// (HotStorage 2018). It keeps one set of pages ordered both by recency and
+// by use count, and evicts either the LRU or the LFU page, choosing between
+// the two by weights learned from regret. Where ARC moves its target p by a
+// fixed rule on ghost hits, LeCaR moves its weights multiplicatively.
+//
+// Time is counted in lookups.
+type LeCaR[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+
+	clock     int
+	recency   *LRU[K, V]        // Every page, by recency, with its value
+	frequency *LFU[K, struct{}] // Every page, by use count
+	history   [2]*LRU[K, int]   // Time each expert evicted a key, by expert
+	experts   experts
+
+	hits   int
+	misses int
+}
+
+// NewLeCaR returns a LeCaR holding pages bindings of up to limit/pages
+// bytes, learning at the given rate and choosing experts with the given
+// seed. Each expert remembers as many keys as there are pages. The paper
+// uses a learning rate of 0.45.
+func NewLeCaR[K comparable, V any](limit int, pages int, rate float64, seed int64) *LeCaR[K, V] {
+	return &LeCaR[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		recency:        NewLru[K, V](limit, pages),
+		frequency:      NewLFU[K, struct{}](limit, pages, 0),
+		history:        [2]*LRU[K, int]{newHistory[K, int](pages), newHistory[K, int](pages)},
+		experts:        newExperts(pages, rate, seed),
+	}
+}
+
+func (lecar *LeCaR[K, V]) MaxPages() int {
+	return lecar.num_pages
+}
+
+func (lecar *LeCaR[K, V]) RemainingPages() int {
+	return lecar.num_pages - lecar.Len()
+}
+
+// Get returns the value of key, making it most recently used and counting
+// a use of it.
+func (lecar *LeCaR[K, V]) Get(key K) (value V, ok bool) {
+	lecar.clock += 1
+	v, ok := lecar.recency.Peek(key)
+	if !ok {
+		lecar.misses += 1
+		return value, false
+	}
+	lecar.recency.Set(key, v)
+	lecar.frequency.touch(lecar.frequency.entries[key])
+	lecar.hits += 1
+	return v, true
+}
+
+// Set adds the binding, learning from the histories and evicting the page
+// chosen by one of the experts if the cache is full. Setting a cached key
+// replaces its value where it is.
+func (lecar *LeCaR[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > lecar.bytes_per_page {
+		return false
+	}
+	if lecar.recency.Contains(key) {
+		lecar.recency.update(key, value)
+		return true
+	}
+	for expert, history := range lecar.history {
+		if evicted, ok := history.Remove(key); ok {
+			lecar.experts.regret(expert, lecar.clock-evicted)
+		}
+	}
+
+	if lecar.Len() == lecar.num_pages {
+		expert := lecar.experts.choose()
+		var victim K
+		if expert == lecarLRU {
+			victim, _ = lecar.recency.oldest()
+		} else {
+			victim = lecar.frequency.victim(false)
+		}
+		lecar.recency.Remove(victim)
+		lecar.frequency.remove(victim)
+		lecar.history[expert].Set(victim, lecar.clock)
+	}
+	lecar.recency.Set(key, value)
+	lecar.frequency.insert(key, struct{}{})
+	return true
+}
+
+func (lecar *LeCaR[K, V]) Len() int {
+	return lecar.recency.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (lecar *LeCaR[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   lecar.hits,
+		Misses: lecar.misses,
+	}
+}
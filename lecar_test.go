@@ -0,0 +1,128 @@
+/******************************************************************************
+ * lecar_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lecar.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that regret moves weight to the other expert, more for recent
+// mistakes
+func TestExpertsRegret(t *testing.T) {
+	x := newExperts(pages, 0.45, 1)
+	x.regret(lecarLRU, 0)
+	if x.weight[lecarLFU] <= 0.5 || x.weight[lecarLRU]+x.weight[lecarLFU] < 0.999 {
+		t.Errorf("Failed to reward the other expert. Weights are: %v", x.weight)
+		t.FailNow()
+	}
+
+	recent, old := newExperts(pages, 0.45, 1), newExperts(pages, 0.45, 1)
+	recent.regret(lecarLFU, 1)
+	old.regret(lecarLFU, 4*pages)
+	if recent.weight[lecarLRU] <= old.weight[lecarLRU] {
+		t.Errorf("Failed to discount old mistakes. Weights are: %v and %v", recent.weight, old.weight)
+		t.FailNow()
+	}
+}
+
+// Checks that evicted keys are remembered by the expert that chose them, and
+// that the experts learn from the histories
+func TestLeCaRHistory(t *testing.T) {
+	lecar := NewLeCaR[string, []byte](limit, pages, 0.45, 1)
+
+	addCache(lecar, 1, 8)
+	addCache(lecar, 9, 9)
+	if lecar.Len() != pages {
+		t.Errorf("Wrong number of pages. Length is: %d", lecar.Len())
+		t.FailNow()
+	}
+	// key1 is both the LRU and the oldest LFU page, whichever expert chose
+	if lecar.recency.Contains("key1") || lecar.history[0].Len()+lecar.history[1].Len() != 1 {
+		t.Errorf("Failed to evict into a history. Histories hold: %d and %d", lecar.history[0].Len(), lecar.history[1].Len())
+		t.FailNow()
+	}
+	expert := lecarLRU
+	if lecar.history[lecarLFU].Contains("key1") {
+		expert = lecarLFU
+	}
+
+	addCache(lecar, 1, 1)
+	if lecar.history[expert].Contains("key1") || lecar.experts.weight[expert] >= 0.5 {
+		t.Errorf("Failed to learn from a history hit. Weights are: %v", lecar.experts.weight)
+		t.FailNow()
+	}
+}
+
+// Checks that the experts evict the pages their policies pick
+func TestLeCaREviction(t *testing.T) {
+	for _, expert := range []int{lecarLRU, lecarLFU} {
+		lecar := NewLeCaR[string, []byte](limit, pages, 0.45, 1)
+		lecar.experts.weight = [2]float64{1, 0}
+		if expert == lecarLFU {
+			lecar.experts.weight = [2]float64{0, 1}
+		}
+
+		addCache(lecar, 1, 8)
+		lecar.Get("key1")
+		lecar.Get("key1")
+		for i := 2; i <= 8; i++ {
+			lecar.Get(fmt.Sprintf("key%d", i))
+		}
+		lecar.Get("key2")
+		// key1 is the LRU page and key3 the oldest least frequently used
+		addCache(lecar, 9, 9)
+
+		victim := "key1"
+		if expert == lecarLFU {
+			victim = "key3"
+		}
+		if lecar.recency.Contains(victim) || !lecar.history[expert].Contains(victim) {
+			t.Errorf("Failed to evict the page of expert %d. Key is: %s", expert, victim)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks the bounds on a random workload
+func TestLeCaRInvariants(t *testing.T) {
+	lecar := NewLeCaR[int, int](1024, 16, 0.45, 1)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i
+		}
+		if _, ok := lecar.Get(key); !ok {
+			lecar.Set(key, key)
+		}
+		if lecar.Len() > 16 || lecar.frequency.Len() != lecar.Len() {
+			t.Errorf("Broke the size bound. Lengths are: %d and %d", lecar.Len(), lecar.frequency.Len())
+			t.FailNow()
+		}
+		if lecar.history[0].Len() > 16 || lecar.history[1].Len() > 16 {
+			t.Errorf("Broke the history bound. Histories hold: %d and %d", lecar.history[0].Len(), lecar.history[1].Len())
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkLeCaR(b *testing.B) {
+	benchHits(b, NewLeCaR[int, int](1024, 64, 0.45, 1))
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"container/list"
+)
+
+// An LFU evicts the least frequently used page, breaking ties by recency,
+// in O(1) with the frequency lists of Shah, Mitra and Matani (2010). Pages
+// are kept in buckets of equal use count, and the buckets in a list of
+// increasing count, so a use moves a page to the next bucket and the page
+// to evict is the oldest one in the first bucket. Counts can optionally
+// be aged by halving them every few lookups, so that pages that were
+// popular long ago do not stay forever.
+type LFU[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	aging          int // Lookups between halvings of every count, 0 for never
+	lookups        int // Lookups since the last halving
+
+	buckets *list.List // lfuBuckets by increasing count
+	entries map[K]*lfuEntry[K, V]
+
+	hits   int
+	misses int
+}
+
+type lfuBucket[K comparable, V any] struct {
+	count   int
+	entries *list.List // lfuEntries with this count; the front is the oldest
+}
+
+type lfuEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *list.Element // Position of the entry's bucket in buckets
+	pos    *list.Element // Position in the bucket
+}
+
+// NewLFU returns an LFU holding pages bindings of up to limit/pages bytes,
+// halving every use count each aging lookups, or never if aging is 0.
+func NewLFU[K comparable, V any](limit int, pages int, aging int) *LFU[K, V] {
+	return &LFU[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		aging:          max(aging, 0),
+		buckets:        list.New(),
+		entries:        make(map[K]*lfuEntry[K, V]),
+	}
+}
+
+func (lfu *LFU[K, V]) MaxPages() int {
+	return lfu.num_pages
+}
+
+func (lfu *LFU[K, V]) RemainingPages() int {
+	return lfu.num_pages - lfu.Len()
+}
+
+// Get returns the value of key and counts a use of it.
+func (lfu *LFU[K, V]) Get(key K) (value V, ok bool) {
+	if lfu.aging > 0 {
+		lfu.lookups += 1
+		if lfu.lookups == lfu.aging {
+			lfu.age()
+			lfu.lookups = 0
+		}
+	}
+	e, ok := lfu.entries[key]
+	if !ok {
+		lfu.misses += 1
+		return value, false
+	}
+	lfu.touch(e)
+	lfu.hits += 1
+	return e.value, true
+}
+
+// Set adds the binding with a use count of one, evicting the least
+// frequently used page if the cache is full. Setting a cached key replaces
+// its value and counts a use.
+func (lfu *LFU[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > lfu.bytes_per_page {
+		return false
+	}
+	if e, ok := lfu.entries[key]; ok {
+		e.value = value
+		lfu.touch(e)
+		return true
+	}
+	if lfu.Len() == lfu.num_pages {
+		lfu.remove(lfu.victim(false))
+	}
+	lfu.insert(key, value)
+	return true
+}
+
+// insert adds a binding that is not cached with a use count of one.
+func (lfu *LFU[K, V]) insert(key K, value V) {
+	first := lfu.buckets.Front()
+	if first == nil || first.Value.(*lfuBucket[K, V]).count != 1 {
+		first = lfu.buckets.PushFront(&lfuBucket[K, V]{count: 1, entries: list.New()})
+	}
+	e := &lfuEntry[K, V]{key: key, value: value, bucket: first}
+	e.pos = first.Value.(*lfuBucket[K, V]).entries.PushBack(e)
+	lfu.entries[key] = e
+}
+
+// touch moves an entry to the bucket of the next count, creating it if
+// needed and removing the old bucket if it is left empty.
+func (lfu *LFU[K, V]) touch(e *lfuEntry[K, V]) {
+	cur := e.bucket.Value.(*lfuBucket[K, V])
+	next := e.bucket.Next()
+	if next == nil || next.Value.(*lfuBucket[K, V]).count != cur.count+1 {
+		next = lfu.buckets.InsertAfter(&lfuBucket[K, V]{count: cur.count + 1, entries: list.New()}, e.bucket)
+	}
+	lfu.move(e, next)
+}
+
+// move takes an entry out of its bucket, removing the bucket if it is left
+// empty, and adds it as the newest entry of bucket.
+func (lfu *LFU[K, V]) move(e *lfuEntry[K, V], bucket *list.Element) {
+	old := e.bucket.Value.(*lfuBucket[K, V])
+	old.entries.Remove(e.pos)
+	if old.entries.Len() == 0 {
+		lfu.buckets.Remove(e.bucket)
+	}
+	e.bucket = bucket
+	e.pos = bucket.Value.(*lfuBucket[K, V]).entries.PushBack(e)
+}
+
+// victim returns the key of the oldest of the least frequently used pages,
+// or of the newest if newest is set. The cache must not be empty.
+func (lfu *LFU[K, V]) victim(newest bool) K {
+	entries := lfu.buckets.Front().Value.(*lfuBucket[K, V]).entries
+	if newest {
+		return entries.Back().Value.(*lfuEntry[K, V]).key
+	}
+	return entries.Front().Value.(*lfuEntry[K, V]).key
+}
+
+// remove takes key out of the cache, if it is there.
+func (lfu *LFU[K, V]) remove(key K) {
+	e, ok := lfu.entries[key]
+	if !ok {
+		return
+	}
+	bucket := e.bucket.Value.(*lfuBucket[K, V])
+	bucket.entries.Remove(e.pos)
+	if bucket.entries.Len() == 0 {
+		lfu.buckets.Remove(e.bucket)
+	}
+	delete(lfu.entries, key)
+}
+
+// age halves every use count, keeping counts of at least one. Buckets whose
+// counts become equal are merged, the lower one's pages counting as older.
+func (lfu *LFU[K, V]) age() {
+	for b := lfu.buckets.Front(); b != nil; {
+		next := b.Next()
+		bucket := b.Value.(*lfuBucket[K, V])
+		bucket.count = max(bucket.count/2, 1)
+		if prev := b.Prev(); prev != nil && prev.Value.(*lfuBucket[K, V]).count == bucket.count {
+			for bucket.entries.Len() > 0 {
+				lfu.move(bucket.entries.Front().Value.(*lfuEntry[K, V]), prev)
+			}
+		}
+		b = next
+	}
+}
+
+func (lfu *LFU[K, V]) Len() int {
+	return len(lfu.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (lfu *LFU[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   lfu.hits,
+		Misses: lfu.misses,
+	}
+}
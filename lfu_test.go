@@ -0,0 +1,141 @@
+/******************************************************************************
+ * lfu_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lfu.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// Checks that the buckets have increasing counts, are not empty and hold
+// every entry
+func checkBuckets[K comparable, V any](lfu *LFU[K, V], t *testing.T) {
+	count, entries := 0, 0
+	for b := lfu.buckets.Front(); b != nil; b = b.Next() {
+		bucket := b.Value.(*lfuBucket[K, V])
+		if bucket.count <= count || bucket.entries.Len() == 0 {
+			t.Errorf("Found a bucket out of order or empty. Count is: %d after %d", bucket.count, count)
+			t.FailNow()
+		}
+		count = bucket.count
+		entries += bucket.entries.Len()
+	}
+	if entries != lfu.Len() {
+		t.Errorf("Lost entries from the buckets. Found %d of %d", entries, lfu.Len())
+		t.FailNow()
+	}
+}
+
+// Returns the use count of key
+func lfuCount[V any](lfu *LFU[string, V], key string) int {
+	return lfu.entries[key].bucket.Value.(*lfuBucket[string, V]).count
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that the least frequently used page is evicted, oldest first
+func TestLFUEviction(t *testing.T) {
+	lfu := NewLFU[string, []byte](limit, pages, 0)
+
+	addCache(lfu, 1, 8)
+	addCache(lfu, 9, 9)
+	if _, ok := lfu.entries["key1"]; ok {
+		t.Errorf("Failed to evict the oldest page of equal counts. Key is: %s", "key1")
+		t.FailNow()
+	}
+
+	for i := 2; i <= 7; i++ {
+		lfu.Get(fmt.Sprintf("key%d", i))
+	}
+	addCache(lfu, 10, 11)
+	for _, key := range []string{"key8", "key9"} {
+		if _, ok := lfu.entries[key]; ok {
+			t.Errorf("Failed to evict the least frequently used page. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+	if lfuCount(lfu, "key2") != 2 || lfuCount(lfu, "key11") != 1 {
+		t.Errorf("Miscounted uses. Counts are: %d and %d", lfuCount(lfu, "key2"), lfuCount(lfu, "key11"))
+		t.FailNow()
+	}
+	checkBuckets(lfu, t)
+}
+
+// Checks that aging halves every count and merges buckets
+func TestLFUAging(t *testing.T) {
+	lfu := NewLFU[string, []byte](limit, pages, 0)
+
+	addCache(lfu, 1, 8)
+	for i := 0; i < 5; i++ {
+		lfu.Get("key1")
+	}
+	lfu.Get("key2")
+	lfu.Get("key2")
+	lfu.Get("key3")
+
+	lfu.age()
+	for key, want := range map[string]int{"key1": 3, "key2": 1, "key3": 1, "key4": 1} {
+		if got := lfuCount(lfu, key); got != want {
+			t.Errorf("Failed to halve a count. Count of %s is: %d when it should be %d", key, got, want)
+			t.FailNow()
+		}
+	}
+	if lfu.buckets.Len() != 2 {
+		t.Errorf("Failed to merge buckets. Buckets are: %d when they should be %d", lfu.buckets.Len(), 2)
+		t.FailNow()
+	}
+	checkBuckets(lfu, t)
+
+	// Aging every 4 lookups: 4 uses are halved before the last one counts
+	lfu = NewLFU[string, []byte](limit, pages, 4)
+	addCache(lfu, 1, 1)
+	for i := 0; i < 4; i++ {
+		lfu.Get("key1")
+	}
+	if got := lfuCount(lfu, "key1"); got != 3 {
+		t.Errorf("Failed to age after a number of lookups. Count is: %d when it should be %d", got, 3)
+		t.FailNow()
+	}
+}
+
+// Checks the buckets on a random workload with aging
+func TestLFUInvariants(t *testing.T) {
+	lfu := NewLFU[int, int](1024, 16, 100)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := lfu.Get(key); !ok {
+			lfu.Set(key, key)
+		}
+		if lfu.Len() > 16 {
+			t.Errorf("Broke the size bound. Length is: %d", lfu.Len())
+			t.FailNow()
+		}
+		checkBuckets(lfu, t)
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkLFU(b *testing.B) {
+	benchHits(b, NewLFU[int, int](1024, 64, 0))
+}
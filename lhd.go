@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	lhdClasses     = 16      // Classes of bindings, by uses rounded to a power of two
+	lhdAges        = 1024    // Age buckets per class
+	lhdSamples     = 64      // Bindings compared per eviction
+	lhdReconfigure = 1 << 12 // Lookups between recomputing hit densities
+	lhdDecay       = 0.9     // Weight of the past events at each recomputation
+	lhdOverflow    = 0.01    // Share of events in the last age bucket that coarsens ages
+	lhdExplorers   = 0.01    // Share of the cache that explorers may take
+	lhdExploreOdds = 32      // One in this many new bindings explores, budget permitting
+)
+
+// An LHD is the Least Hit Density policy of Beckmann, Chen and Cidon
+// (NSDI 2018). A binding's hit density is the chance that it is hit before
+// it is evicted, divided by the space-time it will take until then: its
+// size times its expected remaining lifetime. Both are predicted from how
+// bindings of the same class and age were hit or evicted in the past, and
+// LHD evicts the binding of lowest hit density among a random sample.
+//
+// Ages are counted in lookups, coarsened to powers of two as needed so the
+// age buckets cover the lifetimes seen in the trace. Bindings that are
+// always evicted young would never show whether they are hit when older,
+// so a few new bindings are explorers that are kept until the last age.
+type LHD[K comparable, V any] struct {
+	limit    int // Total number of bytes in the cache
+	used     int
+	explored int // Bytes held by explorers
+	budget   int // Bytes explorers may hold
+	size     SizeFunc[K, V]
+	rand     *rand.Rand
+
+	clock    int
+	shift    int // Ages are counted in units of 2^shift lookups
+	entries  map[K]*lhdEntry[K, V]
+	resident []*lhdEntry[K, V] // Every entry, for sampling
+	classes  [lhdClasses]lhdClass
+
+	hits   int
+	misses int
+}
+
+// An lhdClass holds the hits and evictions seen at each age, and the hit
+// density they predict.
+type lhdClass struct {
+	hits      [lhdAges]float64
+	evictions [lhdAges]float64
+	density   [lhdAges]float64
+}
+
+type lhdEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	size     int
+	uses     int
+	last     int  // Time of the last use
+	explorer bool // Kept until the last age
+	index    int  // Position in resident
+}
+
+// NewLHD returns an LHD holding limit bytes of bindings measured by size,
+// sampling bindings to evict with the given seed. Bindings count as at least
+// one byte, so that ones of size 0 are evicted too. MaxPages and
+// RemainingPages count bytes.
+func NewLHD[K comparable, V any](limit int, size SizeFunc[K, V], seed int64) *LHD[K, V] {
+	lhd := &LHD[K, V]{
+		limit:   limit,
+		budget:  max(int(lhdExplorers*float64(limit)), 1),
+		size:    atLeastOneByte(size),
+		rand:    rand.New(rand.NewSource(seed)),
+		entries: make(map[K]*lhdEntry[K, V]),
+	}
+	// Until there is data, rank bindings by age, as LRU would
+	for c := range lhd.classes {
+		for a := range lhd.classes[c].density {
+			lhd.classes[c].density[a] = 1 / float64(a+1)
+		}
+	}
+	return lhd
+}
+
+func (lhd *LHD[K, V]) MaxPages() int {
+	return lhd.limit
+}
+
+func (lhd *LHD[K, V]) RemainingPages() int {
+	return lhd.limit - lhd.used
+}
+
+// Bytes returns the total size of the bindings in the cache.
+func (lhd *LHD[K, V]) Bytes() int {
+	return lhd.used
+}
+
+// age returns the age bucket of an entry.
+func (lhd *LHD[K, V]) age(e *lhdEntry[K, V]) int {
+	return min((lhd.clock-e.last)>>lhd.shift, lhdAges-1)
+}
+
+// class returns the class of an entry.
+func (lhd *LHD[K, V]) class(e *lhdEntry[K, V]) *lhdClass {
+	return &lhd.classes[min(bits.Len(uint(e.uses)), lhdClasses-1)]
+}
+
+// Get returns the value of key, recording the hit at its age.
+func (lhd *LHD[K, V]) Get(key K) (value V, ok bool) {
+	lhd.clock += 1
+	if lhd.clock%lhdReconfigure == 0 {
+		lhd.reconfigure()
+	}
+	e, ok := lhd.entries[key]
+	if !ok {
+		lhd.misses += 1
+		return value, false
+	}
+	lhd.class(e).hits[lhd.age(e)] += 1
+	e.uses += 1
+	e.last = lhd.clock
+	lhd.hits += 1
+	return e.value, true
+}
+
+// Set adds the binding, evicting bindings of low hit density until it
+// fits. Setting a cached key replaces its value and keeps its uses. A
+// binding larger than the whole cache is rejected.
+func (lhd *LHD[K, V]) Set(key K, value V) bool {
+	size := lhd.size(key, value)
+	if size > lhd.limit {
+		return false
+	}
+	e, ok := lhd.entries[key]
+	if ok {
+		lhd.remove(e)
+	} else {
+		e = &lhdEntry[K, V]{key: key, last: lhd.clock}
+	}
+	for lhd.used+size > lhd.limit {
+		lhd.evict()
+	}
+	e.value = value
+	e.size = size
+	e.index = len(lhd.resident)
+	lhd.resident = append(lhd.resident, e)
+	lhd.entries[key] = e
+	lhd.used += size
+	if !ok && lhd.explored+size <= lhd.budget && lhd.rand.Intn(lhdExploreOdds) == 0 {
+		e.explorer = true
+		lhd.explored += size
+	}
+	return true
+}
+
+// evict removes the entry of lowest hit density per byte among a sample,
+// recording the eviction at its age.
+func (lhd *LHD[K, V]) evict() {
+	var victim *lhdEntry[K, V]
+	lowest := 0.0
+	for i := 0; i < min(lhdSamples, len(lhd.resident)); i++ {
+		e := lhd.resident[lhd.rand.Intn(len(lhd.resident))]
+		density := lhd.class(e).density[lhd.age(e)] / float64(e.size)
+		if e.explorer && lhd.age(e) < lhdAges-1 {
+			density = math.Inf(1)
+		}
+		if victim == nil || density < lowest {
+			victim, lowest = e, density
+		}
+	}
+	lhd.class(victim).evictions[lhd.age(victim)] += 1
+	lhd.remove(victim)
+}
+
+// remove takes an entry out of the cache.
+func (lhd *LHD[K, V]) remove(e *lhdEntry[K, V]) {
+	last := lhd.resident[len(lhd.resident)-1]
+	lhd.resident[e.index] = last
+	last.index = e.index
+	lhd.resident = lhd.resident[:len(lhd.resident)-1]
+	delete(lhd.entries, e.key)
+	lhd.used -= e.size
+	if e.explorer {
+		e.explorer = false
+		lhd.explored -= e.size
+	}
+}
+
+// reconfigure recomputes the hit density of every class and age from the
+// events seen, then decays the events. The density at age a is the hits
+// expected at ages from a on, over the lifetime expected from a on.
+func (lhd *LHD[K, V]) reconfigure() {
+	events, overflow := 0.0, 0.0
+	for c := range lhd.classes {
+		class := &lhd.classes[c]
+		hits, ended, lifetime := 0.0, 0.0, 0.0
+		for a := lhdAges - 1; a >= 0; a-- {
+			hits += class.hits[a]
+			ended += class.hits[a] + class.evictions[a]
+			lifetime += ended
+			if lifetime > 0 {
+				class.density[a] = hits / lifetime
+			} else {
+				class.density[a] = 0
+			}
+		}
+		events += ended
+		overflow += class.hits[lhdAges-1] + class.evictions[lhdAges-1]
+		for a := range class.hits {
+			class.hits[a] *= lhdDecay
+			class.evictions[a] *= lhdDecay
+		}
+	}
+	if events > 0 && overflow/events > lhdOverflow {
+		lhd.shift += 1
+	}
+}
+
+func (lhd *LHD[K, V]) Len() int {
+	return len(lhd.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (lhd *LHD[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   lhd.hits,
+		Misses: lhd.misses,
+	}
+}
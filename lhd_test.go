@@ -0,0 +1,121 @@
+/******************************************************************************
+ * lhd_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lhd.go, following the behaviours described
+ *    in the LHD paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks the hit densities computed from the hits and evictions at each age
+func TestLHDReconfigure(t *testing.T) {
+	lhd := NewLHD(100, valueSize, 1)
+
+	// Ten hits at age 5 and ten evictions at age 20: from age 0 the expected
+	// lifetime is 10*6 + 10*21 in total, for 10 hits
+	class := &lhd.classes[0]
+	class.hits[5] = 10
+	class.evictions[20] = 10
+	lhd.reconfigure()
+
+	if got, want := class.density[0], 10.0/270; math.Abs(got-want) > 1e-12 {
+		t.Errorf("Wrong hit density at age 0. Density is: %g when it should be %g", got, want)
+		t.FailNow()
+	}
+	if got, want := class.density[6], 0.0; got != want {
+		t.Errorf("Predicted hits after the last hit age. Density is: %g", got)
+		t.FailNow()
+	}
+	if class.density[0] >= class.density[5] {
+		t.Errorf("Failed to raise the density of bindings close to their hit age. Densities are: %g and %g", class.density[0], class.density[5])
+		t.FailNow()
+	}
+	if class.hits[5] != 10*lhdDecay || lhd.shift != 0 {
+		t.Errorf("Failed to decay the events. Hits are: %g", class.hits[5])
+		t.FailNow()
+	}
+
+	// Too many events past the last age coarsens ages
+	class.evictions[lhdAges-1] = 10
+	lhd.reconfigure()
+	if lhd.shift != 1 {
+		t.Errorf("Failed to coarsen ages on overflow. Shift is: %d", lhd.shift)
+		t.FailNow()
+	}
+}
+
+// Checks that of bindings with the same hit density the largest is evicted
+func TestLHDSizeAware(t *testing.T) {
+	lhd := NewLHD(100, valueSize, 1)
+
+	lhd.Set("big", 50)
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		lhd.Set(key, 10)
+	}
+	lhd.Set("f", 10)
+
+	if _, ok := lhd.entries["big"]; ok {
+		t.Errorf("Failed to evict the binding of lowest hit density per byte. Key is: %s", "big")
+		t.FailNow()
+	}
+	if lhd.Bytes() != 60 || lhd.Len() != 6 {
+		t.Errorf("Failed to account the eviction. Bytes are: %d, length is: %d", lhd.Bytes(), lhd.Len())
+		t.FailNow()
+	}
+}
+
+// Checks the byte budget and bookkeeping on a random workload of sizes
+func TestLHDBounds(t *testing.T) {
+	lhd := NewLHD(1000, valueSize, 1)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(200)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off bindings
+		}
+		k := string(rune(key))
+		if _, ok := lhd.Get(k); !ok {
+			lhd.Set(k, 1+key%50)
+		}
+
+		if lhd.Bytes() > 1000 || lhd.explored > lhd.budget || len(lhd.resident) != lhd.Len() {
+			t.Errorf("Broke the bounds. Bytes %d, explorers %d, resident %d of %d", lhd.Bytes(), lhd.explored, len(lhd.resident), lhd.Len())
+			t.FailNow()
+		}
+	}
+}
+
+// Checks that bindings of size 0 count as one byte, so they are evicted
+func TestLHDZeroSize(t *testing.T) {
+	lhd := NewLHD(10, valueSize, 1)
+
+	for i := 0; i < 100; i++ {
+		lhd.Set(fmt.Sprintf("key%d", i), 0)
+	}
+	if lhd.Len() != 10 || lhd.Bytes() != 10 {
+		t.Errorf("Failed to bound bindings of size 0. Length is: %d and bytes are: %d", lhd.Len(), lhd.Bytes())
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkLHD(b *testing.B) {
+	benchHits(b, NewLHD(64, func(int, int) int { return 1 }, 1))
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"container/list"
+)
+
+// A LIRS is the Low Inter-reference Recency Set policy of Jiang and Zhang
+// (SIGMETRICS 2002). Pages whose last two uses were close together are LIR
+// and always resident; the rest are HIR and share a few resident pages
+// kept in a FIFO queue, Q. The stack S orders LIR pages, resident HIR pages
+// and the keys of recently evicted non-resident HIR pages by recency, and
+// is pruned so that its bottom is always a LIR page: an HIR page used again
+// while still in S was reused more recently than the oldest LIR page, so it
+// becomes LIR in its place.
+type LIRS[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	lir_pages      int // Pages held by LIR bindings once warm
+	max_ghosts     int // Non-resident HIR keys remembered in S
+
+	lir_count int
+	entries   map[K]*lirsEntry[K, V]
+	s         *list.List // Recency stack; front is the bottom
+	q         *list.List // FIFO of resident HIR entries; front is evicted
+	ghosts    *list.List // Non-resident HIR entries; front is forgotten
+
+	hits   int
+	misses int
+}
+
+type lirsStatus int
+
+const (
+	lirsLIR         lirsStatus = iota
+	lirsHIR                    // Resident HIR
+	lirsNonResident            // Non-resident HIR, only a key in S
+)
+
+type lirsEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	status lirsStatus
+	s      *list.Element // Position in S, nil if pruned
+	q      *list.Element // Position in Q, for resident HIR entries
+	ghost  *list.Element // Position in ghosts, for non-resident entries
+}
+
+// NewLIRS returns a LIRS cache holding pages bindings of up to limit/pages
+// bytes, hirs of which are resident HIR pages, and remembering up to ghosts
+// non-resident HIR keys. The paper suggests hirs of 1% of the pages; at
+// least one page is left to each set, except that a single page is LIR.
+func NewLIRS[K comparable, V any](limit int, pages int, hirs int, ghosts int) *LIRS[K, V] {
+	hirs = min(max(hirs, 1), pages-1)
+	return &LIRS[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		lir_pages:      pages - hirs,
+		max_ghosts:     max(ghosts, 0),
+		entries:        make(map[K]*lirsEntry[K, V]),
+		s:              list.New(),
+		q:              list.New(),
+		ghosts:         list.New(),
+	}
+}
+
+func (lirs *LIRS[K, V]) MaxPages() int {
+	return lirs.num_pages
+}
+
+func (lirs *LIRS[K, V]) RemainingPages() int {
+	return lirs.num_pages - lirs.Len()
+}
+
+// Get returns the value of a resident key and moves it to the top of S,
+// making a resident HIR page LIR if it was still in S.
+func (lirs *LIRS[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := lirs.entries[key]
+	if !ok || e.status == lirsNonResident {
+		lirs.misses += 1
+		return value, false
+	}
+	lirs.hits += 1
+
+	switch {
+	case e.status == lirsLIR:
+		lirs.s.MoveToBack(e.s)
+		lirs.prune()
+	case e.s != nil:
+		lirs.q.Remove(e.q)
+		e.q = nil
+		lirs.makeLIR(e)
+	default:
+		e.s = lirs.s.PushBack(e)
+		lirs.q.MoveToBack(e.q)
+	}
+	return e.value, true
+}
+
+// Set adds a binding, as LIR while the cache is warming up or if its key
+// is a non-resident HIR page still in S, and as resident HIR otherwise.
+// Setting a resident key replaces its value where it is.
+func (lirs *LIRS[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > lirs.bytes_per_page {
+		return false
+	}
+	e, ok := lirs.entries[key]
+	if ok && e.status != lirsNonResident {
+		e.value = value
+		return true
+	}
+
+	if lirs.Len() == lirs.num_pages {
+		lirs.evict()
+	}
+	if ok {
+		// evict may have pruned the entry from S and forgotten it
+		e, ok = lirs.entries[key]
+	}
+	if ok {
+		lirs.ghosts.Remove(e.ghost)
+		e.ghost = nil
+		e.value = value
+		lirs.makeLIR(e)
+		return true
+	}
+
+	e = &lirsEntry[K, V]{key: key, value: value, status: lirsHIR}
+	lirs.entries[key] = e
+	e.s = lirs.s.PushBack(e)
+	if lirs.lir_count < lirs.lir_pages {
+		e.status = lirsLIR
+		lirs.lir_count += 1
+		return true
+	}
+	e.q = lirs.q.PushBack(e)
+	return true
+}
+
+// makeLIR moves an HIR entry in S to the top of S as LIR, and demotes the
+// LIR entry at the bottom of S to resident HIR if there are too many.
+func (lirs *LIRS[K, V]) makeLIR(e *lirsEntry[K, V]) {
+	e.status = lirsLIR
+	lirs.lir_count += 1
+	lirs.s.MoveToBack(e.s)
+	if lirs.lir_count > lirs.lir_pages {
+		lirs.demote()
+	}
+}
+
+// demote turns the LIR entry at the bottom of S into a resident HIR entry
+// at the end of Q, and prunes S.
+func (lirs *LIRS[K, V]) demote() {
+	bottom := lirs.s.Front().Value.(*lirsEntry[K, V])
+	lirs.s.Remove(bottom.s)
+	bottom.s = nil
+	bottom.status = lirsHIR
+	bottom.q = lirs.q.PushBack(bottom)
+	lirs.lir_count -= 1
+	lirs.prune()
+}
+
+// prune removes HIR entries from the bottom of S until it is a LIR entry.
+// Pruned resident entries stay in Q; pruned non-resident ones are forgotten.
+func (lirs *LIRS[K, V]) prune() {
+	for lirs.s.Len() > 0 {
+		bottom := lirs.s.Front().Value.(*lirsEntry[K, V])
+		if bottom.status == lirsLIR {
+			return
+		}
+		lirs.s.Remove(bottom.s)
+		bottom.s = nil
+		if bottom.status == lirsNonResident {
+			lirs.ghosts.Remove(bottom.ghost)
+			delete(lirs.entries, bottom.key)
+		}
+	}
+}
+
+// evict frees a page by evicting the resident HIR entry at the front of Q,
+// keeping its key in S as non-resident if it is still there.
+func (lirs *LIRS[K, V]) evict() {
+	if lirs.q.Len() == 0 {
+		lirs.demote()
+	}
+	e := lirs.q.Remove(lirs.q.Front()).(*lirsEntry[K, V])
+	e.q = nil
+	if e.s == nil {
+		delete(lirs.entries, e.key)
+		return
+	}
+	var zero V
+	e.value = zero
+	e.status = lirsNonResident
+	e.ghost = lirs.ghosts.PushBack(e)
+	if lirs.ghosts.Len() > lirs.max_ghosts {
+		old := lirs.ghosts.Remove(lirs.ghosts.Front()).(*lirsEntry[K, V])
+		lirs.s.Remove(old.s)
+		delete(lirs.entries, old.key)
+	}
+}
+
+// Len returns the number of resident bindings.
+func (lirs *LIRS[K, V]) Len() int {
+	return lirs.lir_count + lirs.q.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (lirs *LIRS[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   lirs.hits,
+		Misses: lirs.misses,
+	}
+}
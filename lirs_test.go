@@ -0,0 +1,186 @@
+/******************************************************************************
+ * lirs_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lirs.go, following the behaviours described
+ *    in the LIRS paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// Checks the status of key and whether it is still in the stack S
+func checkLIRS[V any](lirs *LIRS[string, V], t *testing.T, key string, status lirsStatus, inStack bool) {
+	e, ok := lirs.entries[key]
+	if !ok {
+		t.Errorf("Forgot a key that should still be known. Key is: %s", key)
+		t.FailNow()
+	}
+	if e.status != status {
+		t.Errorf("Wrong status. Status of %s is: %d when it should be %d", key, e.status, status)
+		t.FailNow()
+	}
+	if (e.s != nil) != inStack {
+		t.Errorf("Wrong stack membership. %s in S is: %t when it should be %t", key, e.s != nil, inStack)
+		t.FailNow()
+	}
+}
+
+// Touches keys start to end, making LIR keys the most recent in S
+func getLIRS[V any](lirs *LIRS[string, V], start int, end int) {
+	for i := start; i <= end; i++ {
+		lirs.Get(fmt.Sprintf("key%d", i))
+	}
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that the first pages become LIR and the rest resident HIR
+func TestLIRSWarmUp(t *testing.T) {
+	lirs := NewLIRS[string, []byte](limit, pages, 2, pages)
+
+	addCache(lirs, 1, 8)
+	for i := 1; i <= 6; i++ {
+		checkLIRS(lirs, t, fmt.Sprintf("key%d", i), lirsLIR, true)
+	}
+	checkLIRS(lirs, t, "key7", lirsHIR, true)
+	checkLIRS(lirs, t, "key8", lirsHIR, true)
+	if lirs.Len() != pages || lirs.q.Len() != 2 {
+		t.Errorf("Failed to fill the cache. Length is: %d, Q is: %d", lirs.Len(), lirs.q.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that using the LIR page at the bottom of S prunes the resident
+// HIR pages below the next LIR page, which stay cached in Q
+func TestLIRSStackPruning(t *testing.T) {
+	lirs := NewLIRS[string, []byte](limit, pages, 2, pages)
+
+	addCache(lirs, 1, 7)
+	// S from the bottom: key1, key7, key2-6
+	getLIRS(lirs, 2, 6)
+	checkLIRS(lirs, t, "key7", lirsHIR, true)
+
+	// Move key1 to the top, leaving key7 at the bottom
+	getLIRS(lirs, 1, 1)
+	checkLIRS(lirs, t, "key7", lirsHIR, false)
+	if bottom := lirs.s.Front().Value.(*lirsEntry[string, []byte]); bottom.key != "key2" {
+		t.Errorf("Failed to prune S down to a LIR page. Bottom is: %s", bottom.key)
+		t.FailNow()
+	}
+	if _, ok := lirs.Get("key7"); !ok {
+		t.Errorf("Pruning evicted a resident HIR page. Key is: %s", "key7")
+		t.FailNow()
+	}
+	// A resident HIR page outside S goes back to S, but stays HIR
+	checkLIRS(lirs, t, "key7", lirsHIR, true)
+}
+
+// Checks that pruning forgets non-resident HIR pages
+func TestLIRSPruneNonResident(t *testing.T) {
+	lirs := NewLIRS[string, []byte](limit, pages, 2, pages)
+
+	// key9 evicts key7 from Q, which stays in S as non-resident
+	addCache(lirs, 1, 9)
+	checkLIRS(lirs, t, "key7", lirsNonResident, true)
+	if _, ok := lirs.Get("key7"); ok {
+		t.Errorf("Hit a non-resident page. Key is: %s", "key7")
+		t.FailNow()
+	}
+
+	// S from the bottom: key1, key7-9, key2-6
+	getLIRS(lirs, 2, 6)
+	getLIRS(lirs, 1, 1)
+	if _, ok := lirs.entries["key7"]; ok {
+		t.Errorf("Failed to forget a pruned non-resident page. Key is: %s", "key7")
+		t.FailNow()
+	}
+	checkLIRS(lirs, t, "key8", lirsHIR, false)
+	checkLIRS(lirs, t, "key9", lirsHIR, false)
+	if lirs.ghosts.Len() != 0 || lirs.s.Len() != 6 {
+		t.Errorf("Failed to prune S. S is: %d, non-resident pages are: %d", lirs.s.Len(), lirs.ghosts.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that a non-resident page requested while still in S becomes LIR
+// and the LIR page at the bottom of S is demoted
+func TestLIRSPromotion(t *testing.T) {
+	lirs := NewLIRS[string, []byte](limit, pages, 2, pages)
+
+	addCache(lirs, 1, 9)
+	addCache(lirs, 7, 7)
+
+	checkLIRS(lirs, t, "key7", lirsLIR, true)
+	checkLIRS(lirs, t, "key1", lirsHIR, false)
+	checkLIRS(lirs, t, "key8", lirsNonResident, true)
+	if lirs.lir_count != 6 || lirs.Len() != pages {
+		t.Errorf("Lost count of LIR pages. LIR pages are: %d, length is: %d", lirs.lir_count, lirs.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that a long scan of new pages does not flush LIR pages
+func TestLIRSScanResistance(t *testing.T) {
+	lirs := NewLIRS[string, []byte](limit, pages, 2, pages)
+
+	addCache(lirs, 1, 6)
+	addCache(lirs, 1000, 1100)
+
+	for i := 1; i <= 6; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := lirs.Get(key); !ok {
+			t.Errorf("Scan flushed a LIR page. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks the invariants of LIRS on a random workload
+func TestLIRSInvariants(t *testing.T) {
+	lirs := NewLIRS[int, int](1024, 16, 3, 16)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := lirs.Get(key); !ok {
+			lirs.Set(key, key)
+		}
+
+		if lirs.Len() > 16 || lirs.lir_count > 13 || lirs.ghosts.Len() > 16 {
+			t.Errorf("Broke the size bounds. LIR %d, Q %d, non-resident %d", lirs.lir_count, lirs.q.Len(), lirs.ghosts.Len())
+			t.FailNow()
+		}
+		if bottom := lirs.s.Front().Value.(*lirsEntry[int, int]); bottom.status != lirsLIR {
+			t.Errorf("Found an HIR page at the bottom of S. Key is: %d", bottom.key)
+			t.FailNow()
+		}
+		if len(lirs.entries) != lirs.Len()+lirs.ghosts.Len() {
+			t.Errorf("Lost track of entries. Known %d, resident %d, non-resident %d", len(lirs.entries), lirs.Len(), lirs.ghosts.Len())
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkLIRS(b *testing.B) {
+	benchHits(b, NewLIRS[int, int](1024, 64, 2, 128))
+}
@@ -1,63 +1,95 @@
-package main
+package cache
 
 import (
 	"container/list"
-	"fmt"
 )
 
 // An LRU is a fixed-size in-memory cache with least-recently-used eviction
-// user gives size of cache and number of pages
-type LRU struct {
-	// whatever fields you want here
+// user gives size of cache and number of pages, or just the size of the cache
+// when bindings are accounted by their size in bytes
+type LRU[K comparable, V any] struct {
 	max_pages     int
 	page_size     int
 	total_size    int
 	current_pages int
+	bytes_used    int
+	byte_mode     bool
 	stat          Stats
-	pairMap       map[any]Value
+	size          SizeFunc[K, V]
+	pairMap       map[K]Value[V]
 	keyQueue      *list.List
 }
 
-type Value struct {
-	keySize  int
-	value    []byte
+type Value[V any] struct {
+	value    V
+	size     int
 	queuePos *list.Element
 }
 
 // NewLRU returns a pointer to a new LRU with a capacity to store limit bytes
-func NewLru(total_cache int, num_pages int) *LRU {
-	newLRU := new(LRU)
+// split evenly across num_pages pages. Bindings are measured with DefaultSize.
+func NewLru[K comparable, V any](total_cache int, num_pages int) *LRU[K, V] {
+	newLRU := new(LRU[K, V])
 	newLRU.total_size = total_cache
 	newLRU.max_pages = num_pages
 	newLRU.page_size = total_cache / num_pages
 	newLRU.current_pages = 0
 	newLRU.stat.Hits = 0
 	newLRU.stat.Misses = 0
-	newLRU.pairMap = make(map[any]Value)
+	newLRU.size = DefaultSize[K, V]
+	newLRU.pairMap = make(map[K]Value[V])
 	newLRU.keyQueue = list.New()
 	return newLRU
 }
 
-func (lru *LRU) Len() int {
+// NewByteLru returns a pointer to a new LRU that holds bindings of any size
+// as long as their total, as measured by size, stays within limit bytes.
+// Bindings count as at least one byte, so that ones of size 0 are evicted too.
+// MaxPages and RemainingPages count bytes for such an LRU.
+func NewByteLru[K comparable, V any](limit int, size SizeFunc[K, V]) *LRU[K, V] {
+	newLRU := NewLru[K, V](limit, 1)
+	newLRU.max_pages = limit
+	newLRU.byte_mode = true
+	newLRU.size = atLeastOneByte(size)
+	return newLRU
+}
+
+// atLeastOneByte returns size, counting every binding as at least one byte
+// so that a byte budget also bounds the number of bindings.
+func atLeastOneByte[K comparable, V any](size SizeFunc[K, V]) SizeFunc[K, V] {
+	return func(key K, value V) int {
+		return max(size(key, value), 1)
+	}
+}
+
+func (lru *LRU[K, V]) Len() int {
 	return lru.current_pages
 }
 
-func (lru *LRU) MaxPages() int {
+func (lru *LRU[K, V]) MaxPages() int {
 	return lru.max_pages
 }
 
-func (lru *LRU) RemainingPages() int {
+func (lru *LRU[K, V]) RemainingPages() int {
+	if lru.byte_mode {
+		return lru.total_size - lru.bytes_used
+	}
 	return lru.max_pages - lru.current_pages
 }
 
-func (lru *LRU) Contains(key string) (ok bool) {
+// Bytes returns the total size of the bindings in the cache.
+func (lru *LRU[K, V]) Bytes() int {
+	return lru.bytes_used
+}
+
+func (lru *LRU[K, V]) Contains(key K) (ok bool) {
 	_, ok = lru.pairMap[key]
 	return ok
 }
 
 // Get returns whether or not the key was found. true for hit, false for miss.
 // hits and misses are updated
-func (lru *LRU) Get(key string) (value []byte, ok bool) {
+func (lru *LRU[K, V]) Get(key K) (value V, ok bool) {
 	v, ok := lru.pairMap[key]
 	if ok {
 		// remove instance from queue then add to back. use move to back
@@ -66,60 +98,96 @@ func (lru *LRU) Get(key string) (value []byte, ok bool) {
 		return v.value, ok
 	}
 	lru.stat.Misses++
-	return nil, ok
+	return value, ok
 }
 
-func (lru *LRU) Remove(key string) (value []byte, ok bool) {
+// Peek returns the value of key without counting a use or a hit.
+func (lru *LRU[K, V]) Peek(key K) (value V, ok bool) {
+	v, ok := lru.pairMap[key]
+	return v.value, ok
+}
+
+// update replaces the value of key in place, without counting a use.
+func (lru *LRU[K, V]) update(key K, value V) {
+	v := lru.pairMap[key]
+	size := lru.size(key, value)
+	lru.bytes_used += size - v.size
+	v.value = value
+	v.size = size
+	lru.pairMap[key] = v
+}
+
+func (lru *LRU[K, V]) Remove(key K) (value V, ok bool) {
 	v, ok := lru.pairMap[key]
 	if ok {
 		delete(lru.pairMap, key)
 		lru.keyQueue.Remove(v.queuePos)
 		lru.current_pages--
+		lru.bytes_used -= v.size
 		return v.value, true
 	}
-	return nil, false
+	return value, false
+}
+
+// oldest returns the least recently used key without removing it.
+func (lru *LRU[K, V]) oldest() (key K, ok bool) {
+	if front := lru.keyQueue.Front(); front != nil {
+		return front.Value.(K), true
+	}
+	return key, false
+}
+
+// newest returns the most recently used key without removing it.
+func (lru *LRU[K, V]) newest() (key K, ok bool) {
+	if back := lru.keyQueue.Back(); back != nil {
+		return back.Value.(K), true
+	}
+	return key, false
 }
 
 // Removing oldest key from lru and returns whether or not something was evicted
-func (lru *LRU) RemoveLRU() (key string, value []byte) {
+func (lru *LRU[K, V]) RemoveLRU() (key K, value V) {
 
 	// front is oldest
 	toEvict := lru.keyQueue.Front()
-	v, ok := lru.pairMap[toEvict.Value]
-	if toEvict == nil || !ok {
-		// fmt.Println("evicting from empty list")
-		return "", nil
+	if toEvict == nil {
+		return key, value
 	}
+	key = toEvict.Value.(K)
+	v := lru.pairMap[key]
 
 	lru.keyQueue.Remove(toEvict)
 
-	delete(lru.pairMap, toEvict.Value)
+	delete(lru.pairMap, key)
 	lru.current_pages--
-	valStr := fmt.Sprintf("%v", toEvict.Value)
-	return valStr, v.value
+	lru.bytes_used -= v.size
+	return key, v.value
 }
 
 //this works for both replacing and adding
-func (lru *LRU) Add(key string, value []byte) {
-	//
-	newV := new(Value)
-	newV.value = value
-
-	_ = lru.keyQueue.PushBack(key)
-	newV.queuePos = lru.keyQueue.Back()
-	newV.queuePos.Value = key
-	lru.pairMap[key] = *newV
+func (lru *LRU[K, V]) Add(key K, value V) {
+	lru.add(key, value, lru.size(key, value))
+}
 
+func (lru *LRU[K, V]) add(key K, value V, size int) {
+	newV := Value[V]{value: value, size: size}
+	newV.queuePos = lru.keyQueue.PushBack(key)
+	lru.pairMap[key] = newV
+	lru.bytes_used += size
 }
 
 // Set associates the given value with the given key, possibly evicting values
 // to make room. Returns true if the binding was added successfully, else false.
-func (lru *LRU) Set(key string, value []byte) bool {
+func (lru *LRU[K, V]) Set(key K, value V) bool {
 
 	// if size of key and value are greater than remaining space, then evict until
 	// they are less than remaining space, making sure we stop evicting when cache
 	// is empty
-	if len(value)+len(key) > lru.page_size {
+	size := lru.size(key, value)
+	if lru.byte_mode {
+		return lru.setBytes(key, value, size)
+	}
+	if size > lru.page_size {
 		return false
 	}
 
@@ -130,19 +198,34 @@ func (lru *LRU) Set(key string, value []byte) bool {
 		if lru.current_pages == lru.max_pages {
 			lru.RemoveLRU()
 		}
-		lru.Add(key, value)
+		lru.add(key, value, size)
 		lru.current_pages++
 
 	} else {
 		lru.keyQueue.Remove(v.queuePos)
-		lru.Add(key, value)
+		lru.bytes_used -= v.size
+		lru.add(key, value, size)
 
 	}
 
 	return true
 }
 
+// setBytes evicts the oldest bindings until the new one fits in the byte budget.
+func (lru *LRU[K, V]) setBytes(key K, value V, size int) bool {
+	if size > lru.total_size {
+		return false
+	}
+	lru.Remove(key)
+	for lru.bytes_used+size > lru.total_size {
+		lru.RemoveLRU()
+	}
+	lru.add(key, value, size)
+	lru.current_pages++
+	return true
+}
+
 // Stats returns statistics about how many search hits and misses have occurred.
-func (lru *LRU) Stats() *Stats {
+func (lru *LRU[K, V]) Stats() *Stats {
 	return &lru.stat
 }
@@ -0,0 +1,127 @@
+/******************************************************************************
+ * lru_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lru.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that the least recently used key is the one evicted
+func TestLRUEvictsOldest(t *testing.T) {
+	lru := NewLru[string, []byte](limit, pages)
+
+	for i := 1; i <= 8; i++ {
+		key := fmt.Sprintf("key%d", i)
+		lru.Set(key, []byte(key))
+	}
+
+	// Touch key1 so key2 becomes the oldest
+	lru.Get("key1")
+	lru.Set("key9", []byte("key9"))
+
+	if _, ok := lru.Get("key2"); ok {
+		t.Errorf("Failed to evict the least recently used key. Key is: %s", "key2")
+		t.FailNow()
+	}
+	if _, ok := lru.Get("key1"); !ok {
+		t.Errorf("Evicted a recently used key. Key is: %s", "key1")
+		t.FailNow()
+	}
+	if lru.Len() != 8 {
+		t.Errorf("Overfilled the cache. Length is: %d when it should be %d", lru.Len(), 8)
+		t.FailNow()
+	}
+}
+
+// Checks that removing from an empty cache returns zero values
+func TestLRURemoveEmpty(t *testing.T) {
+	lru := NewLru[string, []byte](limit, pages)
+
+	key, val := lru.RemoveLRU()
+	if key != "" || val != nil {
+		t.Errorf("Failed to return zero values from an empty cache. Key is: %s. Value is: %s", key, val)
+		t.FailNow()
+	}
+	if lru.Len() != 0 {
+		t.Errorf("Failed to keep an empty cache empty. Length is: %d", lru.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that keys and values of any type can be cached
+func TestLRUGenericTypes(t *testing.T) {
+	type point struct{ x, y int }
+	lru := NewLru[int, point](limit, pages)
+
+	for i := 1; i <= 9; i++ {
+		lru.Set(i, point{i, -i})
+	}
+
+	if _, ok := lru.Get(1); ok {
+		t.Errorf("Failed to cache miss on a removed key. Key is: %d", 1)
+		t.FailNow()
+	}
+	val, ok := lru.Get(9)
+	if !ok || val != (point{9, -9}) {
+		t.Errorf("Wrong value: %v for binding with key: %d", val, 9)
+		t.FailNow()
+	}
+}
+
+// Checks that a byte-mode LRU evicts as many bindings as needed
+func TestLRUByteMode(t *testing.T) {
+	lru := NewByteLru[string, int](100, func(key string, value int) int { return value })
+
+	lru.Set("a", 30)
+	lru.Set("b", 30)
+	lru.Set("c", 30)
+	lru.Set("d", 70)
+
+	// Just enough is evicted for d to fit
+	for _, key := range []string{"a", "b"} {
+		if _, ok := lru.Get(key); ok {
+			t.Errorf("Failed to cache miss on a removed key. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+	if lru.Bytes() != 100 || lru.Len() != 2 {
+		t.Errorf("Wrong size. Bytes are: %d and length is: %d when they should be %d and %d", lru.Bytes(), lru.Len(), 100, 2)
+		t.FailNow()
+	}
+
+	// Growing a binding in place evicts others rather than itself
+	lru.Set("e", 20)
+	lru.Set("e", 40)
+	if _, ok := lru.Get("d"); ok {
+		t.Errorf("Failed to cache miss on a removed key. Key is: %s", "d")
+		t.FailNow()
+	}
+	if lru.Bytes() != 40 {
+		t.Errorf("Wrong value: %d for bytes used. Value should be: %d", lru.Bytes(), 40)
+		t.FailNow()
+	}
+}
+
+// Checks that bindings of size 0 count as one byte, so they are evicted
+func TestLRUByteModeZeroSize(t *testing.T) {
+	lru := NewByteLru[int, int](10, func(key int, value int) int { return value })
+
+	for key := 0; key < 100; key++ {
+		lru.Set(key, 0)
+	}
+	if lru.Len() != 10 || lru.Bytes() != 10 {
+		t.Errorf("Failed to bound bindings of size 0. Length is: %d and bytes are: %d", lru.Len(), lru.Bytes())
+		t.FailNow()
+	}
+}
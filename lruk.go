@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"container/heap"
+)
+
+// An LRUK is the LRU-K policy of O'Neil, O'Neil and Weikum (SIGMOD 1993).
+// It evicts the page whose K-th most recent reference is oldest, so pages
+// referenced fewer than K times go first, oldest first. References closer
+// than the correlated reference period to the page's last one count as the
+// same reference, and the history of evicted pages is kept for a while so
+// that a page brought back in does not start over.
+//
+// Time is counted in lookups.
+type LRUK[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	k              int
+	crp            int // Correlated reference period, in lookups
+
+	clock   int
+	entries map[K]*lrukEntry[K, V]
+	queue   lrukQueue[K, V]   // Resident entries by K-th reference, oldest first
+	history *LRU[K, lrukHist] // References of evicted pages
+
+	hits   int
+	misses int
+}
+
+// lrukHist holds a page's last K uncorrelated reference times, most recent
+// first, with 0 for references that never happened, and the time of its
+// last reference of any kind.
+type lrukHist struct {
+	refs []int
+	last int
+}
+
+type lrukEntry[K comparable, V any] struct {
+	key   K
+	value V
+	hist  lrukHist
+	index int // Position in the queue
+}
+
+// NewLRUK returns an LRU-K holding pages bindings of up to limit/pages
+// bytes, treating references within crp lookups of each other as
+// correlated and keeping the history of up to history evicted pages. The
+// paper finds K = 2 gives most of the benefit.
+func NewLRUK[K comparable, V any](limit int, pages int, k int, crp int, history int) *LRUK[K, V] {
+	return &LRUK[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		k:              max(k, 1),
+		crp:            max(crp, 0),
+		entries:        make(map[K]*lrukEntry[K, V]),
+		history:        newHistory[K, lrukHist](max(history, 1)),
+	}
+}
+
+func (lruk *LRUK[K, V]) MaxPages() int {
+	return lruk.num_pages
+}
+
+func (lruk *LRUK[K, V]) RemainingPages() int {
+	return lruk.num_pages - lruk.Len()
+}
+
+// Get returns the value of key and records the reference.
+func (lruk *LRUK[K, V]) Get(key K) (value V, ok bool) {
+	lruk.clock += 1
+	e, ok := lruk.entries[key]
+	if !ok {
+		lruk.misses += 1
+		return value, false
+	}
+	if lruk.clock-e.hist.last > lruk.crp {
+		// A new uncorrelated reference: close the correlated period by
+		// shifting the older references forward by its length
+		period := e.hist.last - e.hist.refs[0]
+		for i := lruk.k - 1; i > 0; i-- {
+			if e.hist.refs[i-1] > 0 {
+				e.hist.refs[i] = e.hist.refs[i-1] + period
+			}
+		}
+		e.hist.refs[0] = lruk.clock
+		heap.Fix(&lruk.queue, e.index)
+	}
+	e.hist.last = lruk.clock
+	lruk.hits += 1
+	return e.value, true
+}
+
+// Set adds the binding as referenced now, evicting a page if the cache is
+// full. Setting a cached key replaces its value without a reference.
+func (lruk *LRUK[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > lruk.bytes_per_page {
+		return false
+	}
+	if e, ok := lruk.entries[key]; ok {
+		e.value = value
+		return true
+	}
+	if lruk.Len() == lruk.num_pages {
+		lruk.evict()
+	}
+
+	e := &lrukEntry[K, V]{key: key, value: value}
+	if hist, ok := lruk.history.Remove(key); ok {
+		e.hist = hist
+		copy(e.hist.refs[1:], e.hist.refs)
+	} else {
+		e.hist.refs = make([]int, lruk.k)
+	}
+	e.hist.refs[0] = lruk.clock
+	e.hist.last = lruk.clock
+	lruk.entries[key] = e
+	heap.Push(&lruk.queue, e)
+	return true
+}
+
+// evict removes the page with the oldest K-th reference among those out of
+// their correlated reference period, or among all pages if none is, and
+// remembers its history.
+func (lruk *LRUK[K, V]) evict() {
+	var skipped []*lrukEntry[K, V]
+	victim := heap.Pop(&lruk.queue).(*lrukEntry[K, V])
+	for lruk.clock-victim.hist.last <= lruk.crp && lruk.queue.Len() > 0 {
+		skipped = append(skipped, victim)
+		victim = heap.Pop(&lruk.queue).(*lrukEntry[K, V])
+	}
+	if lruk.clock-victim.hist.last <= lruk.crp && len(skipped) > 0 {
+		// Every page is in its correlated period
+		skipped = append(skipped, victim)
+		victim = skipped[0]
+		skipped = skipped[1:]
+	}
+	for _, e := range skipped {
+		heap.Push(&lruk.queue, e)
+	}
+	delete(lruk.entries, victim.key)
+	lruk.history.Set(victim.key, victim.hist)
+}
+
+func (lruk *LRUK[K, V]) Len() int {
+	return len(lruk.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (lruk *LRUK[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   lruk.hits,
+		Misses: lruk.misses,
+	}
+}
+
+// lrukQueue is a min-heap of entries by K-th reference, then by most recent
+// uncorrelated reference.
+type lrukQueue[K comparable, V any] []*lrukEntry[K, V]
+
+func (q lrukQueue[K, V]) Len() int { return len(q) }
+
+func (q lrukQueue[K, V]) Less(i, j int) bool {
+	a, b := q[i].hist.refs, q[j].hist.refs
+	if a[len(a)-1] != b[len(b)-1] {
+		return a[len(a)-1] < b[len(b)-1]
+	}
+	return a[0] < b[0]
+}
+
+func (q lrukQueue[K, V]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *lrukQueue[K, V]) Push(x any) {
+	e := x.(*lrukEntry[K, V])
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *lrukQueue[K, V]) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,101 @@
+/******************************************************************************
+ * lruk_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for lruk.go, following the behaviours described
+ *    in the LRU-K paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that pages referenced fewer than K times are evicted first, oldest
+// first, and that the history of an evicted page is kept
+func TestLRUKEviction(t *testing.T) {
+	lruk := NewLRUK[string, []byte](limit, pages, 2, 0, pages)
+
+	requestKeys(lruk, 1, 8)
+	requestKeys(lruk, 1, 4)
+	requestKeys(lruk, 9, 10)
+	for _, key := range []string{"key5", "key6"} {
+		if _, ok := lruk.entries[key]; ok {
+			t.Errorf("Failed to evict the oldest page referenced once. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+	if !lruk.history.Contains("key5") {
+		t.Errorf("Failed to keep the history of an evicted page. Key is: %s", "key5")
+		t.FailNow()
+	}
+
+	// key5 comes back with two references, so key7 goes next
+	requestKeys(lruk, 5, 5)
+	if lruk.entries["key5"].hist.refs[1] == 0 {
+		t.Errorf("Failed to restore the history of a page. Key is: %s", "key5")
+		t.FailNow()
+	}
+	if _, ok := lruk.entries["key7"]; ok {
+		t.Errorf("Evicted a page referenced twice before one referenced once. Key is: %s", "key7")
+		t.FailNow()
+	}
+}
+
+// Checks that references within the correlated reference period count as one
+func TestLRUKCorrelatedReferences(t *testing.T) {
+	lruk := NewLRUK[string, []byte](limit, pages, 2, 3, pages)
+
+	requestKeys(lruk, 1, 1)
+	requestKeys(lruk, 1, 1)
+	if lruk.entries["key1"].hist.refs[1] != 0 {
+		t.Errorf("Counted a correlated reference. References are: %v", lruk.entries["key1"].hist.refs)
+		t.FailNow()
+	}
+
+	requestKeys(lruk, 2, 4)
+	requestKeys(lruk, 1, 1)
+	if refs := lruk.entries["key1"].hist.refs; refs[1] == 0 || refs[0] != lruk.clock {
+		t.Errorf("Failed to count an uncorrelated reference. References are: %v", refs)
+		t.FailNow()
+	}
+
+	// key2-8 are all in their correlated period, so the best of them goes
+	requestKeys(lruk, 5, 9)
+	if _, ok := lruk.entries["key2"]; ok || lruk.Len() != pages {
+		t.Errorf("Failed to evict while every page was correlated. Length is: %d", lruk.Len())
+		t.FailNow()
+	}
+}
+
+// Checks that a long scan of new pages does not flush pages referenced twice
+func TestLRUKScanResistance(t *testing.T) {
+	lruk := NewLRUK[string, []byte](limit, pages, 2, 0, pages)
+
+	requestKeys(lruk, 1, 4)
+	requestKeys(lruk, 1, 4)
+	requestKeys(lruk, 1000, 1100)
+
+	for i := 1; i <= 4; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := lruk.Get(key); !ok {
+			t.Errorf("Scan flushed a page referenced twice. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkLRUK(b *testing.B) {
+	benchHits(b, NewLRUK[int, int](1024, 64, 2, 0, 64))
+}
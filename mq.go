@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"container/list"
+	"math/bits"
+)
+
+// An MQ is the Multi-Queue policy of Zhou, Philbin and Li (USENIX 2001),
+// designed for second level caches whose hits have been filtered by the
+// caches above them. Pages are kept in m LRU queues, queue i holding pages
+// used 2^i to 2^(i+1)-1 times, and evicted from the lowest non-empty queue.
+// A page not used for lifetime lookups drops one queue, so pages that were
+// popular long ago age out. The use counts of evicted pages are remembered
+// in the ghost queue Qout, so a page brought back in does not start over.
+//
+// Time is counted in lookups.
+type MQ[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	lifetime       int
+
+	clock   int
+	queues  []*list.List // mqEntries by queue; the front is the LRU page
+	entries map[K]*mqEntry[K, V]
+	qout    *LRU[K, int] // Use counts of evicted pages
+
+	hits   int
+	misses int
+}
+
+type mqEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	count  int // Uses
+	expire int // Time after which the page drops a queue
+	queue  int
+	pos    *list.Element
+}
+
+// NewMQ returns an MQ holding pages bindings of up to limit/pages bytes in
+// queues LRU queues, demoting pages not used for lifetime lookups and
+// remembering the use counts of up to ghosts evicted pages. The paper uses
+// 8 queues and a Qout of 4 times the pages.
+func NewMQ[K comparable, V any](limit int, pages int, queues int, lifetime int, ghosts int) *MQ[K, V] {
+	mq := &MQ[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		lifetime:       max(lifetime, 1),
+		queues:         make([]*list.List, max(queues, 1)),
+		entries:        make(map[K]*mqEntry[K, V]),
+		qout:           newHistory[K, int](max(ghosts, 1)),
+	}
+	for i := range mq.queues {
+		mq.queues[i] = list.New()
+	}
+	return mq
+}
+
+func (mq *MQ[K, V]) MaxPages() int {
+	return mq.num_pages
+}
+
+func (mq *MQ[K, V]) RemainingPages() int {
+	return mq.num_pages - mq.Len()
+}
+
+// Get returns the value of key, counting a use that may move it to a higher
+// queue, and demotes the pages that have expired.
+func (mq *MQ[K, V]) Get(key K) (value V, ok bool) {
+	mq.clock += 1
+	e, ok := mq.entries[key]
+	if ok {
+		mq.queues[e.queue].Remove(e.pos)
+		e.count += 1
+		mq.push(e)
+		mq.hits += 1
+	} else {
+		mq.misses += 1
+	}
+	mq.adjust()
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Set adds the binding with the use count remembered in Qout, plus one,
+// evicting a page if the cache is full. Setting a cached key replaces its
+// value without counting a use.
+func (mq *MQ[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > mq.bytes_per_page {
+		return false
+	}
+	if e, ok := mq.entries[key]; ok {
+		e.value = value
+		return true
+	}
+	if mq.Len() == mq.num_pages {
+		mq.evict()
+	}
+
+	e := &mqEntry[K, V]{key: key, value: value, count: 1}
+	if count, ok := mq.qout.Remove(key); ok {
+		e.count = count + 1
+	}
+	mq.entries[key] = e
+	mq.push(e)
+	return true
+}
+
+// push adds an entry as most recently used in the queue for its use count.
+func (mq *MQ[K, V]) push(e *mqEntry[K, V]) {
+	e.queue = min(bits.Len(uint(e.count))-1, len(mq.queues)-1)
+	e.expire = mq.clock + mq.lifetime
+	e.pos = mq.queues[e.queue].PushBack(e)
+}
+
+// evict removes the LRU page of the lowest non-empty queue and remembers
+// its use count in Qout.
+func (mq *MQ[K, V]) evict() {
+	for _, q := range mq.queues {
+		if q.Len() > 0 {
+			e := q.Remove(q.Front()).(*mqEntry[K, V])
+			delete(mq.entries, e.key)
+			mq.qout.Set(e.key, e.count)
+			return
+		}
+	}
+}
+
+// adjust moves the LRU page of each queue down a queue if it has expired.
+func (mq *MQ[K, V]) adjust() {
+	for i := 1; i < len(mq.queues); i++ {
+		front := mq.queues[i].Front()
+		if front == nil {
+			continue
+		}
+		e := front.Value.(*mqEntry[K, V])
+		if e.expire < mq.clock {
+			mq.queues[i].Remove(front)
+			e.queue = i - 1
+			e.expire = mq.clock + mq.lifetime
+			e.pos = mq.queues[i-1].PushBack(e)
+		}
+	}
+}
+
+func (mq *MQ[K, V]) Len() int {
+	return len(mq.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (mq *MQ[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   mq.hits,
+		Misses: mq.misses,
+	}
+}
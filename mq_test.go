@@ -0,0 +1,89 @@
+/******************************************************************************
+ * mq_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for mq.go, following the behaviours described
+ *    in the Multi-Queue paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that pages move to the queue of their use count
+func TestMQQueues(t *testing.T) {
+	mq := NewMQ[string, []byte](limit, pages, 4, 100, 4*pages)
+
+	requestKeys(mq, 1, 1)
+	for _, want := range []int{1, 1, 2, 2, 2, 2, 3} {
+		mq.Get("key1")
+		if got := mq.entries["key1"].queue; got != want {
+			t.Errorf("Wrong queue. Queue of %d uses is: %d when it should be %d", mq.entries["key1"].count, got, want)
+			t.FailNow()
+		}
+	}
+	for i := 0; i < 20; i++ {
+		mq.Get("key1")
+	}
+	if got := mq.entries["key1"].queue; got != 3 {
+		t.Errorf("Moved past the last queue. Queue is: %d", got)
+		t.FailNow()
+	}
+}
+
+// Checks that the LRU page of the lowest queue is evicted and its count
+// remembered in Qout
+func TestMQEviction(t *testing.T) {
+	mq := NewMQ[string, []byte](limit, pages, 4, 100, 4*pages)
+
+	requestKeys(mq, 1, 8)
+	requestKeys(mq, 1, 4)
+	requestKeys(mq, 9, 9)
+	if _, ok := mq.entries["key5"]; ok {
+		t.Errorf("Failed to evict the LRU page of the lowest queue. Key is: %s", "key5")
+		t.FailNow()
+	}
+	if count, ok := mq.qout.Peek("key5"); !ok || count != 1 {
+		t.Errorf("Failed to remember the count of an evicted page. Count is: %d", count)
+		t.FailNow()
+	}
+
+	requestKeys(mq, 5, 5)
+	if e := mq.entries["key5"]; e.count != 2 || e.queue != 1 || mq.qout.Contains("key5") {
+		t.Errorf("Failed to restore the count of a page from Qout. Count is: %d", e.count)
+		t.FailNow()
+	}
+}
+
+// Checks that a page not used for its lifetime drops a queue
+func TestMQExpiry(t *testing.T) {
+	mq := NewMQ[string, []byte](limit, pages, 4, 3, 4*pages)
+
+	requestKeys(mq, 1, 1)
+	requestKeys(mq, 1, 1)
+	requestKeys(mq, 2, 4)
+	if mq.entries["key1"].queue != 1 {
+		t.Errorf("Demoted a page before its lifetime. Queue is: %d", mq.entries["key1"].queue)
+		t.FailNow()
+	}
+	requestKeys(mq, 5, 5)
+	if mq.entries["key1"].queue != 0 {
+		t.Errorf("Failed to demote an expired page. Queue is: %d", mq.entries["key1"].queue)
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkMQ(b *testing.B) {
+	benchHits(b, NewMQ[int, int](1024, 64, 8, 64, 256))
+}
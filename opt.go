@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"container/heap"
+	"math"
+)
+
+// never is the next use of a key that is not requested again.
+const never = math.MaxInt
+
+// An OPT is Belady's clairvoyant MIN cache. It is given the whole trace up
+// front and evicts the binding whose next use is farthest in the future, so
+// its hit ratio is an upper bound for any online policy of the same size.
+//
+// OPT has to see the requests in trace order: every request must start with
+// a Get of that key, optionally followed by a Set of the same key on a miss.
+// A binding whose next use is farther than everything it would evict is not
+// admitted at all, in which case Set returns false.
+type OPT[K comparable, V any] struct {
+	limit  int // Total number of pages or bytes in the cache
+	used   int
+	trace  []K
+	next   []int // next[i] is the position of the next request for trace[i]
+	cursor int   // Number of requests seen so far
+	size   SizeFunc[K, V]
+
+	entries map[K]*optEntry[K, V]
+	queue   optQueue[K, V]
+
+	hits   int
+	misses int
+}
+
+type optEntry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int
+	next  int // Position of the next request for key
+	index int // Position in the queue
+}
+
+// NewOPT returns an OPT holding pages bindings for the given trace of keys.
+func NewOPT[K comparable, V any](pages int, trace []K) *OPT[K, V] {
+	return NewByteOPT[K, V](pages, func(K, V) int { return 1 }, trace)
+}
+
+// NewByteOPT returns an OPT holding limit bytes of bindings measured by size.
+// Bindings count as at least one byte, so that ones of size 0 are evicted
+// too. With variable sizes MIN is no longer optimal; evicting by farthest
+// next use is the usual approximation and still a strong baseline.
+func NewByteOPT[K comparable, V any](limit int, size SizeFunc[K, V], trace []K) *OPT[K, V] {
+	next := make([]int, len(trace))
+	last := make(map[K]int)
+	for i := len(trace) - 1; i >= 0; i-- {
+		if j, ok := last[trace[i]]; ok {
+			next[i] = j
+		} else {
+			next[i] = never
+		}
+		last[trace[i]] = i
+	}
+	return &OPT[K, V]{
+		limit:   limit,
+		trace:   trace,
+		next:    next,
+		size:    atLeastOneByte(size),
+		entries: make(map[K]*optEntry[K, V]),
+	}
+}
+
+func (opt *OPT[K, V]) MaxPages() int {
+	return opt.limit
+}
+
+func (opt *OPT[K, V]) RemainingPages() int {
+	return opt.limit - opt.used
+}
+
+// nextUse returns when key is requested after the current request.
+func (opt *OPT[K, V]) nextUse(key K) int {
+	if opt.cursor == 0 || opt.cursor > len(opt.trace) || opt.trace[opt.cursor-1] != key {
+		return never
+	}
+	return opt.next[opt.cursor-1]
+}
+
+// Get advances the trace by one request and returns the value of key if cached.
+func (opt *OPT[K, V]) Get(key K) (value V, ok bool) {
+	opt.cursor++
+	e, ok := opt.entries[key]
+	if !ok {
+		opt.misses++
+		return value, false
+	}
+	e.next = opt.nextUse(key)
+	heap.Fix(&opt.queue, e.index)
+	opt.hits++
+	return e.value, true
+}
+
+// Set admits the binding if that beats keeping what it would evict.
+func (opt *OPT[K, V]) Set(key K, value V) bool {
+	size := opt.size(key, value)
+	if size > opt.limit {
+		return false
+	}
+	next := opt.nextUse(key)
+	if e, ok := opt.entries[key]; ok {
+		opt.remove(e)
+	}
+
+	// Only evict bindings that are needed later than this one
+	var victims []*optEntry[K, V]
+	freed := 0
+	for opt.used-freed+size > opt.limit && opt.queue.Len() > 0 && opt.queue[0].next > next {
+		e := heap.Pop(&opt.queue).(*optEntry[K, V])
+		victims = append(victims, e)
+		freed += e.size
+	}
+	if opt.used-freed+size > opt.limit {
+		for _, e := range victims {
+			heap.Push(&opt.queue, e)
+		}
+		return false
+	}
+	for _, e := range victims {
+		delete(opt.entries, e.key)
+		opt.used -= e.size
+	}
+
+	e := &optEntry[K, V]{key: key, value: value, size: size, next: next}
+	heap.Push(&opt.queue, e)
+	opt.entries[key] = e
+	opt.used += size
+	return true
+}
+
+func (opt *OPT[K, V]) remove(e *optEntry[K, V]) {
+	heap.Remove(&opt.queue, e.index)
+	delete(opt.entries, e.key)
+	opt.used -= e.size
+}
+
+func (opt *OPT[K, V]) Len() int {
+	return len(opt.entries)
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (opt *OPT[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   opt.hits,
+		Misses: opt.misses,
+	}
+}
+
+// optQueue is a max-heap of entries ordered by next use.
+type optQueue[K comparable, V any] []*optEntry[K, V]
+
+func (q optQueue[K, V]) Len() int           { return len(q) }
+func (q optQueue[K, V]) Less(i, j int) bool { return q[i].next > q[j].next }
+func (q optQueue[K, V]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *optQueue[K, V]) Push(x any) {
+	e := x.(*optEntry[K, V])
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *optQueue[K, V]) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
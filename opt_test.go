@@ -0,0 +1,119 @@
+/******************************************************************************
+ * opt_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for opt.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// replayKeys looks up every key in order, setting it on a miss.
+func replayKeys(c Cache[string, int], keys []string) *Stats {
+	for _, key := range keys {
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, 1)
+		}
+	}
+	return c.Stats()
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks OPT against the textbook reference string with 3 frames. Demand
+// paging needs 9 faults; MIN may also bypass, which brings it down to 8.
+func TestOPTReferenceString(t *testing.T) {
+	trace := strings.Fields("7 0 1 2 0 3 0 4 2 3 0 3 2 1 2 0 1 7 0 1")
+	opt := NewOPT[string, int](3, trace)
+
+	stats := replayKeys(opt, trace)
+	if !stats.Equals(&Stats{Hits: 12, Misses: 8}) {
+		t.Errorf("Hits are %d. Should be: %d", stats.Hits, 12)
+		t.Errorf("Misses are %d. Should be: %d", stats.Misses, 8)
+		t.FailNow()
+	}
+	if opt.Len() > opt.MaxPages() {
+		t.Errorf("Overfilled the cache. Length is: %d when max is %d", opt.Len(), opt.MaxPages())
+		t.FailNow()
+	}
+}
+
+// Checks that OPT is never beaten by ARC or LRU of the same size
+func TestOPTUpperBound(t *testing.T) {
+	var trace []string
+	for i := 0; i < 2000; i++ {
+		trace = append(trace, fmt.Sprintf("key%d", (i*i+i/3)%37))
+	}
+
+	opt := replayKeys(NewOPT[string, int](8, trace), trace)
+	arc := replayKeys(NewARC[string, int](limit, pages), trace)
+	lru := replayKeys(NewLru[string, int](limit, pages), trace)
+
+	if opt.Hits < arc.Hits || opt.Hits < lru.Hits {
+		t.Errorf("OPT hits %d are below ARC hits %d or LRU hits %d", opt.Hits, arc.Hits, lru.Hits)
+		t.FailNow()
+	}
+}
+
+// Checks that the byte-mode OPT keeps within its budget and skips bindings
+// that are not needed again
+func TestOPTByteMode(t *testing.T) {
+	trace := []string{"a", "b", "c", "a", "b"}
+	sizes := map[string]int{"a": 40, "b": 40, "c": 40}
+	opt := NewByteOPT[string, int](100, func(key string, size int) int { return size }, trace)
+
+	for _, key := range trace {
+		if _, ok := opt.Get(key); !ok {
+			ok = opt.Set(key, sizes[key])
+			if key == "c" && ok {
+				t.Errorf("Admitted a binding that is never used again. Key is: %s", key)
+				t.FailNow()
+			}
+		}
+		if opt.RemainingPages() < 0 {
+			t.Errorf("Overfilled the cache. Remaining bytes are: %d", opt.RemainingPages())
+			t.FailNow()
+		}
+	}
+
+	if !opt.Stats().Equals(&Stats{Hits: 2, Misses: 3}) {
+		t.Errorf("Hits are %d. Should be: %d", opt.Stats().Hits, 2)
+		t.FailNow()
+	}
+}
+
+// Checks that bindings of size 0 count as one byte, so they are evicted
+func TestOPTByteModeZeroSize(t *testing.T) {
+	var trace []string
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		trace = append(trace, key, key)
+	}
+	opt := NewByteOPT[string, int](10, func(key string, size int) int { return size }, trace)
+
+	for _, key := range trace {
+		if _, ok := opt.Get(key); !ok {
+			opt.Set(key, 0)
+		}
+		if opt.Len() > 10 || opt.RemainingPages() < 0 {
+			t.Errorf("Failed to bound bindings of size 0. Length is: %d and remaining bytes are: %d", opt.Len(), opt.RemainingPages())
+			t.FailNow()
+		}
+	}
+	if opt.Stats().Hits != 100 {
+		t.Errorf("Hits are %d. Should be: %d", opt.Stats().Hits, 100)
+		t.FailNow()
+	}
+}
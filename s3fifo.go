@@ -0,0 +1,121 @@
+package cache
+
+// s3MaxFreq caps the access count of S3-FIFO pages at two bits.
+const s3MaxFreq = 3
+
+// An S3FIFO is the Simple, Scalable, Static FIFO policy of Yang et al.
+// (SOSP 2023), built from three FIFO queues. New pages enter the small
+// queue S; when they reach its end they move to the main queue M if they
+// were used while in S, and are otherwise evicted with their keys kept in
+// the ghost queue G. Pages whose keys are in G go straight to M. M is a
+// CLOCK with a small access count per page instead of a bit.
+type S3FIFO[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	small_pages    int // Pages S may hold before it is evicted from first
+
+	small *clockList[K, V]  // S; the front is evicted
+	main  *clockList[K, V]  // M; the front is evicted or reinserted
+	ghost *LRU[K, struct{}] // G, a FIFO of keys evicted from S
+
+	hits   int
+	misses int
+}
+
+// NewS3FIFO returns an S3-FIFO holding pages bindings of up to limit/pages
+// bytes, small of which are in S. G remembers as many keys as M holds. The
+// paper uses 10% of the pages for S.
+func NewS3FIFO[K comparable, V any](limit int, pages int, small int) *S3FIFO[K, V] {
+	small = min(max(small, 1), pages)
+	return &S3FIFO[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		small_pages:    small,
+		small:          newClockList[K, V](),
+		main:           newClockList[K, V](),
+		ghost:          newGhost[K](max(pages-small, 1)),
+	}
+}
+
+func (s3 *S3FIFO[K, V]) MaxPages() int {
+	return s3.num_pages
+}
+
+func (s3 *S3FIFO[K, V]) RemainingPages() int {
+	return s3.num_pages - s3.Len()
+}
+
+// Get returns the value of key and counts the access. Nothing moves.
+func (s3 *S3FIFO[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := s3.small.get(key)
+	if !ok {
+		entry, ok = s3.main.get(key)
+	}
+	if !ok {
+		s3.misses += 1
+		return value, false
+	}
+	entry.freq = min(entry.freq+1, s3MaxFreq)
+	s3.hits += 1
+	return entry.value, true
+}
+
+// Set adds the binding to M if its key is in G, or to S otherwise, evicting
+// a page if the cache is full.
+func (s3 *S3FIFO[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > s3.bytes_per_page {
+		return false
+	}
+	entry, ok := s3.small.get(key)
+	if !ok {
+		entry, ok = s3.main.get(key)
+	}
+	if ok {
+		entry.value = value
+		entry.freq = min(entry.freq+1, s3MaxFreq)
+		return true
+	}
+
+	if s3.Len() == s3.num_pages {
+		s3.evict()
+	}
+	entry = &clockEntry[K, V]{key: key, value: value}
+	if s3.ghost.Contains(key) {
+		s3.ghost.Remove(key)
+		s3.main.push(entry)
+		return true
+	}
+	s3.small.push(entry)
+	return true
+}
+
+// evict frees a page, from S while it holds at least its share of the
+// pages and from M otherwise.
+func (s3 *S3FIFO[K, V]) evict() {
+	for s3.small.Len() >= s3.small_pages {
+		entry := s3.small.pop()
+		if entry.freq == 0 {
+			s3.ghost.Set(entry.key, struct{}{})
+			return
+		}
+		entry.freq = 0
+		s3.main.push(entry)
+	}
+	for s3.main.head().freq > 0 {
+		s3.main.head().freq -= 1
+		s3.main.advance()
+	}
+	s3.main.pop()
+}
+
+func (s3 *S3FIFO[K, V]) Len() int {
+	return s3.small.Len() + s3.main.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (s3 *S3FIFO[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   s3.hits,
+		Misses: s3.misses,
+	}
+}
@@ -0,0 +1,104 @@
+/******************************************************************************
+ * s3fifo_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for s3fifo.go, following the behaviours described
+ *    in the S3-FIFO paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that a page used while in S moves to M, and that a page that was
+// not is evicted with its key kept in G
+func TestS3FIFOPromotion(t *testing.T) {
+	s3 := NewS3FIFO[string, []byte](limit, pages, 2)
+
+	addCache(s3, 1, 8)
+	s3.Get("key1")
+	addCache(s3, 9, 9)
+
+	if !s3.main.Contains("key1") || s3.small.Contains("key1") {
+		t.Errorf("Failed to move a page used in S to M. Key is: %s", "key1")
+		t.FailNow()
+	}
+	if !s3.ghost.Contains("key2") || s3.small.Contains("key2") {
+		t.Errorf("Failed to evict an unused page from S to G. Key is: %s", "key2")
+		t.FailNow()
+	}
+}
+
+// Checks that a page whose key is in G is added to M
+func TestS3FIFOGhost(t *testing.T) {
+	s3 := NewS3FIFO[string, []byte](limit, pages, 2)
+
+	addCache(s3, 1, 9)
+	addCache(s3, 1, 1)
+
+	if !s3.main.Contains("key1") || s3.ghost.Contains("key1") {
+		t.Errorf("Failed to add a page remembered in G to M. Key is: %s", "key1")
+		t.FailNow()
+	}
+	if s3.Len() != pages {
+		t.Errorf("Failed to keep the cache full. Length is: %d when it should be %d", s3.Len(), pages)
+		t.FailNow()
+	}
+}
+
+// Checks that a long scan of new pages does not flush pages in M
+func TestS3FIFOScanResistance(t *testing.T) {
+	s3 := NewS3FIFO[string, []byte](limit, pages, 2)
+
+	addCache(s3, 1, 4)
+	for i := 1; i <= 4; i++ {
+		s3.Get(fmt.Sprintf("key%d", i))
+	}
+	addCache(s3, 1000, 1100)
+
+	for i := 1; i <= 4; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := s3.Get(key); !ok {
+			t.Errorf("Scan flushed a page from M. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks the size bounds of the queues on a random workload
+func TestS3FIFOBounds(t *testing.T) {
+	s3 := NewS3FIFO[int, int](1024, 16, 2)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := s3.Get(key); !ok {
+			s3.Set(key, key)
+		}
+
+		if s3.Len() > 16 || s3.ghost.Len() > 14 {
+			t.Errorf("Broke the size bounds. S %d, M %d, G %d", s3.small.Len(), s3.main.Len(), s3.ghost.Len())
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkS3FIFO(b *testing.B) {
+	benchHits(b, NewS3FIFO[int, int](1024, 64, 6))
+}
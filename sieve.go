@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+)
+
+// A SIEVE is the FIFO-based policy of Zhang et al. (NSDI 2024). Like CLOCK,
+// a hit only sets a visited bit, but new pages are always added at the head
+// of the queue while the hand keeps its position between evictions, sweeping
+// from the oldest page towards the newest. Pages that survive a pass stay
+// where they are, so new pages that are never used again leave quickly.
+type SIEVE[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	pages          *clockList[K, V] // Front is the oldest page
+	hand           *list.Element    // Next page examined; nil starts at the oldest
+
+	hits   int
+	misses int
+}
+
+// NewSIEVE returns a SIEVE holding pages bindings of up to limit/pages bytes.
+func NewSIEVE[K comparable, V any](limit int, pages int) *SIEVE[K, V] {
+	return &SIEVE[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		pages:          newClockList[K, V](),
+	}
+}
+
+func (sieve *SIEVE[K, V]) MaxPages() int {
+	return sieve.num_pages
+}
+
+func (sieve *SIEVE[K, V]) RemainingPages() int {
+	return sieve.num_pages - sieve.pages.Len()
+}
+
+// Get returns the value of key and marks it visited.
+func (sieve *SIEVE[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := sieve.pages.get(key)
+	if !ok {
+		sieve.misses += 1
+		return value, false
+	}
+	entry.ref = true
+	sieve.hits += 1
+	return entry.value, true
+}
+
+// Set adds the binding at the head of the queue, evicting a page if the
+// cache is full.
+func (sieve *SIEVE[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > sieve.bytes_per_page {
+		return false
+	}
+	if entry, ok := sieve.pages.get(key); ok {
+		entry.value = value
+		entry.ref = true
+		return true
+	}
+	if sieve.pages.Len() == sieve.num_pages {
+		sieve.evict()
+	}
+	sieve.pages.push(&clockEntry[K, V]{key: key, value: value})
+	return true
+}
+
+// evict moves the hand towards the newest page, clearing visited bits,
+// and evicts the first page that was not visited.
+func (sieve *SIEVE[K, V]) evict() {
+	queue := sieve.pages.queue
+	hand := sieve.hand
+	if hand == nil {
+		hand = queue.Front()
+	}
+	for hand.Value.(*clockEntry[K, V]).ref {
+		hand.Value.(*clockEntry[K, V]).ref = false
+		if hand = hand.Next(); hand == nil {
+			hand = queue.Front()
+		}
+	}
+	sieve.hand = hand.Next()
+	entry := queue.Remove(hand).(*clockEntry[K, V])
+	delete(sieve.pages.entries, entry.key)
+}
+
+func (sieve *SIEVE[K, V]) Len() int {
+	return sieve.pages.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (sieve *SIEVE[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   sieve.hits,
+		Misses: sieve.misses,
+	}
+}
@@ -0,0 +1,81 @@
+/******************************************************************************
+ * sieve_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for sieve.go, following the behaviours described
+ *    in the SIEVE paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that the hand keeps its position between evictions, skipping and
+// clearing visited pages without moving them
+func TestSIEVEHand(t *testing.T) {
+	sieve := NewSIEVE[string, []byte](limit, pages)
+
+	addCache(sieve, 1, 8)
+	sieve.Get("key1")
+	sieve.Get("key3")
+
+	addCache(sieve, 9, 10)
+	for _, key := range []string{"key2", "key4"} {
+		if _, ok := sieve.pages.get(key); ok {
+			t.Errorf("Failed to evict an unvisited page. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+	if front := sieve.pages.head(); front.key != "key1" || front.ref {
+		t.Errorf("Moved or kept the visited bit of a page the hand passed. Front is: %s", front.key)
+		t.FailNow()
+	}
+
+	// The hand continues from key5 rather than starting over at key1
+	addCache(sieve, 11, 11)
+	if _, ok := sieve.pages.get("key1"); !ok {
+		t.Errorf("Hand restarted at the oldest page. Evicted: %s", "key1")
+		t.FailNow()
+	}
+	if _, ok := sieve.pages.get("key5"); ok {
+		t.Errorf("Failed to evict the page under the hand. Key is: %s", "key5")
+		t.FailNow()
+	}
+}
+
+// Checks that the hand wraps around to the oldest page
+func TestSIEVEWrap(t *testing.T) {
+	sieve := NewSIEVE[string, []byte](limit, pages)
+
+	addCache(sieve, 1, 8)
+	for i := 1; i <= 8; i++ {
+		sieve.Get(fmt.Sprintf("key%d", i))
+	}
+	addCache(sieve, 9, 9)
+	if _, ok := sieve.pages.get("key1"); ok || sieve.Len() != pages {
+		t.Errorf("Failed to wrap around and evict the oldest page. Length is: %d", sieve.Len())
+		t.FailNow()
+	}
+	for i := 2; i <= 8; i++ {
+		if entry, _ := sieve.pages.get(fmt.Sprintf("key%d", i)); entry.ref {
+			t.Errorf("Failed to clear a visited bit. Key is: key%d", i)
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkSIEVE(b *testing.B) {
+	benchHits(b, NewSIEVE[int, int](1024, 64))
+}
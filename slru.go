@@ -0,0 +1,113 @@
+package cache
+
+// A SegmentedLRU is the Segmented LRU of Karedla, Love and Wherry (1994),
+// generalised to any number of segments. New pages enter the lowest,
+// probationary segment, and a hit moves a page to the MRU end of the next
+// segment up. Every segment but the lowest holds at most its share of the
+// pages, and pushes its LRU page down to the MRU end of the segment below
+// when it overflows. Pages are evicted from the LRU end of the lowest
+// segment, so a page has to be used more often to survive the higher it is.
+type SegmentedLRU[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	share          int // Pages each segment above the lowest may hold
+
+	segments []*LRU[K, V] // Lowest, probationary segment first
+
+	hits   int
+	misses int
+}
+
+// NewSegmentedLRU returns a Segmented LRU holding pages bindings of up to
+// limit/pages bytes in the given number of segments, which each hold an
+// equal share of the pages. The classic SLRU has 2 segments.
+func NewSegmentedLRU[K comparable, V any](limit int, pages int, segments int) *SegmentedLRU[K, V] {
+	segments = min(max(segments, 1), pages)
+	slru := &SegmentedLRU[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		share:          pages / segments,
+		segments:       make([]*LRU[K, V], segments),
+	}
+	for i := range slru.segments {
+		slru.segments[i] = NewLru[K, V](limit, pages)
+	}
+	return slru
+}
+
+func (slru *SegmentedLRU[K, V]) MaxPages() int {
+	return slru.num_pages
+}
+
+func (slru *SegmentedLRU[K, V]) RemainingPages() int {
+	return slru.num_pages - slru.Len()
+}
+
+// segment returns the index of the segment holding key, or -1.
+func (slru *SegmentedLRU[K, V]) segment(key K) int {
+	for i, segment := range slru.segments {
+		if segment.Contains(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the value of key, moving it to the MRU end of the next
+// segment up, or of the highest segment if it is already there.
+func (slru *SegmentedLRU[K, V]) Get(key K) (value V, ok bool) {
+	i := slru.segment(key)
+	if i < 0 {
+		slru.misses += 1
+		return value, false
+	}
+	value, _ = slru.segments[i].Remove(key)
+	up := min(i+1, len(slru.segments)-1)
+	slru.segments[up].Set(key, value)
+	slru.demote(up)
+	slru.hits += 1
+	return value, true
+}
+
+// Set adds the binding to the lowest segment, evicting its LRU page if the
+// cache is full. Setting a cached key replaces its value where it is.
+func (slru *SegmentedLRU[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > slru.bytes_per_page {
+		return false
+	}
+	if i := slru.segment(key); i >= 0 {
+		slru.segments[i].update(key, value)
+		return true
+	}
+	if slru.Len() == slru.num_pages {
+		slru.segments[0].RemoveLRU()
+	}
+	return slru.segments[0].Set(key, value)
+}
+
+// demote pushes LRU pages down from segment i and the segments below it
+// while they hold more than their share.
+func (slru *SegmentedLRU[K, V]) demote(i int) {
+	for ; i > 0; i-- {
+		for slru.segments[i].Len() > slru.share {
+			key, value := slru.segments[i].RemoveLRU()
+			slru.segments[i-1].Set(key, value)
+		}
+	}
+}
+
+func (slru *SegmentedLRU[K, V]) Len() int {
+	total := 0
+	for _, segment := range slru.segments {
+		total += segment.Len()
+	}
+	return total
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (slru *SegmentedLRU[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   slru.hits,
+		Misses: slru.misses,
+	}
+}
@@ -0,0 +1,82 @@
+/******************************************************************************
+ * slru_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for slru.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that hits move pages up a segment and that overflowing segments
+// push their LRU pages down
+func TestSegmentedLRUPromotion(t *testing.T) {
+	slru := NewSegmentedLRU[string, []byte](limit, pages, 4)
+
+	addCache(slru, 1, 8)
+	slru.Get("key1")
+	slru.Get("key1")
+	slru.Get("key2")
+	if slru.segment("key1") != 2 || slru.segment("key2") != 1 {
+		t.Errorf("Failed to move pages up. Segments are: %d and %d", slru.segment("key1"), slru.segment("key2"))
+		t.FailNow()
+	}
+
+	for i := 3; i <= 5; i++ {
+		slru.Get(fmt.Sprintf("key%d", i))
+	}
+	// Segment 1 holds 2 pages, so key2 and then key3 were pushed down
+	if slru.segment("key2") != 0 || slru.segment("key3") != 0 || slru.segments[1].Len() != 2 {
+		t.Errorf("Failed to push overflowing pages down. Segments are: %d and %d", slru.segment("key2"), slru.segment("key3"))
+		t.FailNow()
+	}
+
+	addCache(slru, 9, 9)
+	if slru.segment("key6") != -1 || slru.Len() != pages {
+		t.Errorf("Failed to evict the LRU page of the lowest segment. Key is: %s", "key6")
+		t.FailNow()
+	}
+}
+
+// Checks the bounds of every segment on a random workload
+func TestSegmentedLRUInvariants(t *testing.T) {
+	for _, segments := range []int{1, 2, 3, 16} {
+		slru := NewSegmentedLRU[int, int](1024, 16, segments)
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 10000; i++ {
+			key := r.Intn(48)
+			if _, ok := slru.Get(key); !ok {
+				slru.Set(key, key)
+			}
+			if slru.Len() > 16 {
+				t.Errorf("Broke the size bound. Length is: %d", slru.Len())
+				t.FailNow()
+			}
+			for s := 1; s < len(slru.segments); s++ {
+				if slru.segments[s].Len() > slru.share {
+					t.Errorf("Broke the bound of segment %d. Length is: %d", s, slru.segments[s].Len())
+					t.FailNow()
+				}
+			}
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkSegmentedLRU(b *testing.B) {
+	benchHits(b, NewSegmentedLRU[int, int](1024, 64, 2))
+}
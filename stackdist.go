@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// A StackDistance computes the miss ratio of an LRU cache of every size in a
+// single pass over a trace, using Mattson's stack algorithm. The stack
+// distance of a request is its key's position in the LRU stack, that is the
+// number of distinct keys requested since its last request, plus one. An LRU
+// holding c pages hits exactly the requests with a distance of at most c.
+//
+// Last access times are kept in an order-statistic tree, so each request
+// costs O(log n) for n distinct keys. With a sampling rate below 1, only keys
+// whose hash falls under the rate are tracked and their distances scaled, as
+// in SHARDS; this bounds memory for very large traces at some loss of accuracy.
+type StackDistance[K comparable] struct {
+	rate      float64
+	threshold uint64
+	seed      maphash.Seed
+
+	clock int
+	last  map[K]int // Time of the last access of each tracked key
+	times ostree    // Last access times of all tracked keys
+
+	hist      []int   // hist[d] is the number of sampled requests at distance d
+	histBytes []int64 // histBytes[d] is their total size
+	cold      int     // Sampled requests for keys never seen before
+	coldBytes int64
+	requests  int   // All requests, sampled or not
+	allBytes  int64 // Total size of all requests
+	sampled   int
+	bytes     int64 // Total size of sampled requests
+}
+
+// NewStackDistance returns an analyser that tracks every key.
+func NewStackDistance[K comparable]() *StackDistance[K] {
+	return NewSampledStackDistance[K](1)
+}
+
+// NewSampledStackDistance returns an analyser that tracks a rate fraction
+// of the keys, with 0 < rate <= 1. Keys are sampled by a hash seeded per
+// analyser, so sampled curves vary slightly from run to run.
+func NewSampledStackDistance[K comparable](rate float64) *StackDistance[K] {
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	return &StackDistance[K]{
+		rate:      rate,
+		threshold: uint64(rate * math.MaxUint64),
+		seed:      maphash.MakeSeed(),
+		last:      make(map[K]int),
+		hist:      []int{0},
+		histBytes: []int64{0},
+	}
+}
+
+// Access records a request for key of size bytes and returns its stack
+// distance, scaled up when sampling. It returns 0 for the first request of
+// a key and for keys that are not sampled.
+func (sd *StackDistance[K]) Access(key K, size int) int {
+	sd.requests++
+	sd.allBytes += int64(size)
+	if sd.rate < 1 && maphash.Comparable(sd.seed, key) > sd.threshold {
+		return 0
+	}
+	sd.clock++
+	sd.sampled++
+	sd.bytes += int64(size)
+
+	prev, seen := sd.last[key]
+	sd.last[key] = sd.clock
+	if !seen {
+		sd.times.insert(sd.clock)
+		sd.cold++
+		sd.coldBytes += int64(size)
+		return 0
+	}
+
+	distance := sd.times.countGreater(prev) + 1
+	sd.times.remove(prev)
+	sd.times.insert(sd.clock)
+	for len(sd.hist) <= distance {
+		sd.hist = append(sd.hist, 0)
+		sd.histBytes = append(sd.histBytes, 0)
+	}
+	sd.hist[distance]++
+	sd.histBytes[distance] += int64(size)
+	return int(math.Round(float64(distance) / sd.rate))
+}
+
+// Requests returns the number of requests that were sampled.
+func (sd *StackDistance[K]) Requests() int {
+	return sd.sampled
+}
+
+// MissRatio returns the fraction of requests an LRU holding capacity pages
+// would miss.
+//
+// When sampling, misses are divided by the number of requests expected to be
+// sampled rather than by the number actually sampled. This is the SHARDS-adj
+// correction: a very popular key being in or out of the sample skews the
+// count of sampled requests, and almost all of that key's requests are hits.
+func (sd *StackDistance[K]) MissRatio(capacity int) float64 {
+	if sd.sampled == 0 {
+		return 0
+	}
+	misses := sd.cold
+	for d := sd.scaled(capacity) + 1; d < len(sd.hist); d++ {
+		misses += sd.hist[d]
+	}
+	expected := math.Max(sd.rate*float64(sd.requests), float64(misses))
+	return float64(misses) / expected
+}
+
+// ByteMissRatio returns the fraction of requested bytes an LRU holding
+// capacity pages would miss, with the same correction as MissRatio.
+func (sd *StackDistance[K]) ByteMissRatio(capacity int) float64 {
+	if sd.bytes == 0 {
+		return 0
+	}
+	misses := sd.coldBytes
+	for d := sd.scaled(capacity) + 1; d < len(sd.histBytes); d++ {
+		misses += sd.histBytes[d]
+	}
+	expected := math.Max(sd.rate*float64(sd.allBytes), float64(misses))
+	return float64(misses) / expected
+}
+
+// Curve returns the miss ratio at each of the capacities.
+func (sd *StackDistance[K]) Curve(capacities []int) []float64 {
+	curve := make([]float64, len(capacities))
+	for i, c := range capacities {
+		curve[i] = sd.MissRatio(c)
+	}
+	return curve
+}
+
+// scaled returns the capacity as seen by the sampled stack.
+func (sd *StackDistance[K]) scaled(capacity int) int {
+	return int(math.Floor(float64(capacity) * sd.rate))
+}
+
+// ostree is a treap of distinct ints where each node knows the size of its
+// subtree, so counting the values greater than x takes O(log n).
+type ostree struct {
+	root *osnode
+	seed uint64
+}
+
+type osnode struct {
+	value       int
+	priority    uint64
+	size        int
+	left, right *osnode
+}
+
+func (n *osnode) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *osnode) update() {
+	n.size = n.left.count() + n.right.count() + 1
+}
+
+// random returns the next priority from a xorshift generator.
+func (t *ostree) random() uint64 {
+	if t.seed == 0 {
+		t.seed = 0x9e3779b97f4a7c15
+	}
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 7
+	t.seed ^= t.seed << 17
+	return t.seed
+}
+
+func (t *ostree) insert(value int) {
+	t.root = t.insertAt(t.root, &osnode{value: value, priority: t.random(), size: 1})
+}
+
+func (t *ostree) insertAt(n *osnode, node *osnode) *osnode {
+	if n == nil {
+		return node
+	}
+	if node.priority > n.priority {
+		node.left, node.right = split(n, node.value)
+		node.update()
+		return node
+	}
+	if node.value < n.value {
+		n.left = t.insertAt(n.left, node)
+	} else {
+		n.right = t.insertAt(n.right, node)
+	}
+	n.update()
+	return n
+}
+
+func (t *ostree) remove(value int) {
+	t.root = removeAt(t.root, value)
+}
+
+func removeAt(n *osnode, value int) *osnode {
+	if n == nil {
+		return nil
+	}
+	if value == n.value {
+		return merge(n.left, n.right)
+	}
+	if value < n.value {
+		n.left = removeAt(n.left, value)
+	} else {
+		n.right = removeAt(n.right, value)
+	}
+	n.update()
+	return n
+}
+
+// countGreater returns how many values in the tree are greater than value.
+func (t *ostree) countGreater(value int) int {
+	count := 0
+	for n := t.root; n != nil; {
+		if n.value > value {
+			count += n.right.count() + 1
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return count
+}
+
+// split divides n into the values below value and the rest.
+func split(n *osnode, value int) (*osnode, *osnode) {
+	if n == nil {
+		return nil, nil
+	}
+	if n.value < value {
+		l, r := split(n.right, value)
+		n.right = l
+		n.update()
+		return n, r
+	}
+	l, r := split(n.left, value)
+	n.left = r
+	n.update()
+	return l, n
+}
+
+// merge joins two treaps where every value in l is below every value in r.
+func merge(l *osnode, r *osnode) *osnode {
+	if l == nil {
+		return r
+	}
+	if r == nil {
+		return l
+	}
+	if l.priority > r.priority {
+		l.right = merge(l.right, r)
+		l.update()
+		return l
+	}
+	r.left = merge(l, r.left)
+	r.update()
+	return r
+}
@@ -0,0 +1,108 @@
+/******************************************************************************
+ * stackdist_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for stackdist.go, checked against lru.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"bufio"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// readKeys returns the keys of a webcachesim trace in traces/.
+func readKeys(t *testing.T, name string) []string {
+	f, err := os.Open("traces/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		keys = append(keys, strings.Fields(scanner.Text())[1])
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks stack distances on a short sequence
+func TestStackDistanceSimple(t *testing.T) {
+	sd := NewStackDistance[string]()
+
+	want := []int{0, 0, 0, 3, 1, 3, 3}
+	for i, key := range strings.Fields("a b c a a b c") {
+		if d := sd.Access(key, 1); d != want[i] {
+			t.Errorf("Wrong distance: %d for request %d of key: %s. Should be: %d", d, i, key, want[i])
+			t.FailNow()
+		}
+	}
+	if sd.MissRatio(2) != 6.0/7 {
+		t.Errorf("Wrong miss ratio: %f. Should be: %f", sd.MissRatio(2), 6.0/7)
+		t.FailNow()
+	}
+}
+
+// Checks that one pass gives the same miss ratio as replaying an LRU
+func TestStackDistanceMatchesLRU(t *testing.T) {
+	keys := readKeys(t, "trace2.txt")
+
+	sd := NewStackDistance[string]()
+	for _, key := range keys {
+		sd.Access(key, 1)
+	}
+
+	for _, capacity := range []int{1, 8, 50, 100, 500, 2000} {
+		lru := NewLru[string, int](16*capacity, capacity)
+		stats := replayKeys(lru, keys)
+		want := float64(stats.Misses) / float64(len(keys))
+		if got := sd.MissRatio(capacity); got != want {
+			t.Errorf("Wrong miss ratio: %f at capacity %d. LRU misses: %f", got, capacity, want)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks that SHARDS sampling stays close to the exact curve on a trace with
+// many distinct keys
+func TestStackDistanceSampled(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.01, 1, 200000)
+
+	exact := NewStackDistance[uint64]()
+	sampled := NewSampledStackDistance[uint64](0.1)
+	for i := 0; i < 300000; i++ {
+		key := zipf.Uint64()
+		exact.Access(key, 1)
+		sampled.Access(key, 1)
+	}
+
+	if sampled.Requests() >= exact.Requests() {
+		t.Errorf("Failed to sample. Sampled %d of %d requests", sampled.Requests(), exact.Requests())
+		t.FailNow()
+	}
+	for _, capacity := range []int{5000, 20000, 50000} {
+		if diff := math.Abs(sampled.MissRatio(capacity) - exact.MissRatio(capacity)); diff > 0.05 {
+			t.Errorf("Sampled miss ratio is off by %f at capacity %d", diff, capacity)
+			t.FailNow()
+		}
+	}
+}
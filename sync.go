@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// A SyncARC is an ARC that is safe for concurrent use by multiple goroutines.
+// Every operation, including Get, takes the same lock since a hit moves the
+// key between T1 and T2.
+type SyncARC[K comparable, V any] struct {
+	mu  sync.Mutex
+	arc *ARC[K, V]
+}
+
+// NewSyncARC returns a concurrency-safe ARC with the same parameters as NewARC.
+func NewSyncARC[K comparable, V any](limit int, pages int) *SyncARC[K, V] {
+	return &SyncARC[K, V]{arc: NewARC[K, V](limit, pages)}
+}
+
+func (s *SyncARC[K, V]) MaxPages() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.MaxPages()
+}
+
+func (s *SyncARC[K, V]) RemainingPages() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.RemainingPages()
+}
+
+func (s *SyncARC[K, V]) Get(key K) (value V, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.Get(key)
+}
+
+func (s *SyncARC[K, V]) Set(key K, value V) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.Set(key, value)
+}
+
+func (s *SyncARC[K, V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.Len()
+}
+
+// Stats returns a snapshot of the hits and misses so far.
+func (s *SyncARC[K, V]) Stats() *Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arc.Stats()
+}
+
+// A ShardedARC spreads keys across independent SyncARCs so that goroutines
+// working on different keys rarely contend for the same lock. Each shard
+// adapts its own p, so the hit ratio can differ slightly from a single ARC.
+type ShardedARC[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*SyncARC[K, V]
+}
+
+// NewShardedARC splits limit bytes and pages evenly across n shards. Each
+// shard gets at least one page.
+func NewShardedARC[K comparable, V any](limit int, pages int, n int) *ShardedARC[K, V] {
+	if n < 1 {
+		n = 1
+	}
+	if n > pages {
+		n = pages
+	}
+	sharded := &ShardedARC[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*SyncARC[K, V], n),
+	}
+	for i := range sharded.shards {
+		// Hand out the remainder one page at a time to the first shards
+		shardPages := pages / n
+		if i < pages%n {
+			shardPages++
+		}
+		sharded.shards[i] = NewSyncARC[K, V](limit/pages*shardPages, shardPages)
+	}
+	return sharded
+}
+
+// shard returns the ARC responsible for key.
+func (s *ShardedARC[K, V]) shard(key K) *SyncARC[K, V] {
+	h := maphash.Comparable(s.seed, key)
+	return s.shards[h%uint64(len(s.shards))]
+}
+
+// Shards returns the number of shards keys are spread across.
+func (s *ShardedARC[K, V]) Shards() int {
+	return len(s.shards)
+}
+
+func (s *ShardedARC[K, V]) MaxPages() int {
+	total := 0
+	for _, shard := range s.shards {
+		total += shard.MaxPages()
+	}
+	return total
+}
+
+func (s *ShardedARC[K, V]) RemainingPages() int {
+	total := 0
+	for _, shard := range s.shards {
+		total += shard.RemainingPages()
+	}
+	return total
+}
+
+func (s *ShardedARC[K, V]) Get(key K) (value V, ok bool) {
+	return s.shard(key).Get(key)
+}
+
+func (s *ShardedARC[K, V]) Set(key K, value V) bool {
+	return s.shard(key).Set(key, value)
+}
+
+func (s *ShardedARC[K, V]) Len() int {
+	total := 0
+	for _, shard := range s.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+// Stats sums the hits and misses of every shard. Shards are read one at a
+// time, so the result is not an atomic snapshot while other goroutines run.
+func (s *ShardedARC[K, V]) Stats() *Stats {
+	total := &Stats{}
+	for _, shard := range s.shards {
+		stats := shard.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+	}
+	return total
+}
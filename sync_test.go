@@ -0,0 +1,134 @@
+/******************************************************************************
+ * sync_test.go
+ * Author:
+ * Usage:    `go test -race`  or  `go test -bench Parallel -cpu 1,2,4,8`
+ * Description:
+ *    Concurrency tests and parallel benchmarks for sync.go.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// hammer runs goroutines that each look up and set ops keys, and returns the
+// total number of lookups made.
+func hammer(c Cache[string, []byte], goroutines int, ops int) int {
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < ops; i++ {
+				key := fmt.Sprintf("k%d", r.Intn(64))
+				if _, ok := c.Get(key); !ok {
+					c.Set(key, []byte(key))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	return goroutines * ops
+}
+
+// Checks that concurrent use keeps stats and lengths consistent
+func TestSyncARCConcurrent(t *testing.T) {
+	arc := NewSyncARC[string, []byte](1024, 32)
+
+	lookups := hammer(arc, 8, 2000)
+
+	stats := arc.Stats()
+	if stats.Hits+stats.Misses != lookups {
+		t.Errorf("Lost lookups under concurrency. Counted %d when it should be %d", stats.Hits+stats.Misses, lookups)
+		t.FailNow()
+	}
+	if arc.Len() > arc.MaxPages() {
+		t.Errorf("Overfilled the cache. Length is: %d when max is %d", arc.Len(), arc.MaxPages())
+		t.FailNow()
+	}
+}
+
+// Checks that shards split the pages and aggregate stats across shards
+func TestShardedARCConcurrent(t *testing.T) {
+	arc := NewShardedARC[string, []byte](1024, 30, 4)
+
+	if arc.Shards() != 4 {
+		t.Errorf("Wrong number of shards: %d. Should be: %d", arc.Shards(), 4)
+		t.FailNow()
+	}
+	if arc.MaxPages() != 30 {
+		t.Errorf("Wrong value: %d for max pages. Value should be: %d", arc.MaxPages(), 30)
+		t.FailNow()
+	}
+
+	lookups := hammer(arc, 8, 2000)
+
+	stats := arc.Stats()
+	if stats.Hits+stats.Misses != lookups {
+		t.Errorf("Lost lookups under concurrency. Counted %d when it should be %d", stats.Hits+stats.Misses, lookups)
+		t.FailNow()
+	}
+	if arc.Len()+arc.RemainingPages() != arc.MaxPages() {
+		t.Errorf("Inconsistent page counts. Length %d plus remaining %d is not %d", arc.Len(), arc.RemainingPages(), arc.MaxPages())
+		t.FailNow()
+	}
+}
+
+// Checks that a key always maps to the same shard
+func TestShardedARCStableShard(t *testing.T) {
+	arc := NewShardedARC[int, int](1024, 64, 8)
+
+	for i := 0; i < 64; i++ {
+		if arc.shard(i) != arc.shard(i) {
+			t.Errorf("Mapped key: %d to different shards", i)
+			t.FailNow()
+		}
+	}
+
+	// No shard can overflow with as many keys as pages in a shard
+	for i := 0; i < 8; i++ {
+		arc.Set(i, i*i)
+	}
+	for i := 0; i < 8; i++ {
+		val, ok := arc.Get(i)
+		if !ok || val != i*i {
+			t.Errorf("Wrong value: %d for binding with key: %d", val, i)
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+// benchParallel runs a skewed mix of gets and sets from every goroutine.
+func benchParallel(b *testing.B, c Cache[int, int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		zipf := rand.NewZipf(r, 1.1, 1, 1<<16)
+		for pb.Next() {
+			key := int(zipf.Uint64())
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, key)
+			}
+		}
+	})
+}
+
+func BenchmarkSyncARCParallel(b *testing.B) {
+	benchParallel(b, NewSyncARC[int, int](1<<16, 1<<12))
+}
+
+func BenchmarkShardedARCParallel(b *testing.B) {
+	benchParallel(b, NewShardedARC[int, int](1<<16, 1<<12, 64))
+}
@@ -0,0 +1,111 @@
+package cache
+
+const (
+	sketchDepth  = 4  // Rows of the count-min sketch
+	sketchMax    = 15 // Counters saturate at four bits
+	sampleFactor = 10 // Accesses per cached page between resets
+	doorFactor   = 16 // Doorkeeper bits per access in a sample
+)
+
+// A tinyLFU estimates how often keys were accessed recently, as the
+// admission filter of Einziger et al. (TinyLFU, ACM ToS 2017). Counts are
+// kept in a count-min sketch of small saturating counters. The first access
+// to a key only sets its bits in the doorkeeper, a bloom filter, so keys
+// seen once never reach the sketch. After a sample of accesses every
+// counter is halved and the doorkeeper cleared, so old popularity fades.
+type tinyLFU[K comparable] struct {
+	seed   int64
+	mask   uint64 // Counters per row, minus one
+	rows   [sketchDepth][]uint8
+	door   []uint64 // Doorkeeper bits
+	dmask  uint64   // Doorkeeper bits, minus one
+	added  int      // Accesses since the last reset
+	sample int      // Accesses between resets
+}
+
+// newTinyLFU returns a filter sized for a cache of pages bindings, hashing
+// keys with the given seed.
+func newTinyLFU[K comparable](pages int, seed int64) *tinyLFU[K] {
+	width := nextPowerOfTwo(max(4*pages, 64))
+	bits := nextPowerOfTwo(max(doorFactor*sampleFactor*pages, 64))
+	f := &tinyLFU[K]{
+		seed:   seed,
+		mask:   uint64(width - 1),
+		door:   make([]uint64, bits/64),
+		dmask:  uint64(bits - 1),
+		sample: sampleFactor * pages,
+	}
+	for i := range f.rows {
+		f.rows[i] = make([]uint8, width)
+	}
+	return f
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// index returns the counter of the key hashed to h in row i. Each row
+// remixes the hash, so keys that collide in one row rarely collide in all.
+func (f *tinyLFU[K]) index(h uint64, i int) uint64 {
+	h += uint64(i+1) * 0x9e3779b97f4a7c15
+	h ^= h >> 32
+	h *= 0xd6e8feb86659fd93
+	h ^= h >> 32
+	return h & f.mask
+}
+
+// increment counts an access to key, resetting the filter after a sample.
+func (f *tinyLFU[K]) increment(key K) {
+	h := seededHash(f.seed, key)
+	if f.admitDoor(h) {
+		for i := range f.rows {
+			if c := &f.rows[i][f.index(h, i)]; *c < sketchMax {
+				*c += 1
+			}
+		}
+	}
+	f.added += 1
+	if f.added >= f.sample {
+		f.reset()
+	}
+}
+
+// admitDoor reports whether h was already in the doorkeeper, adding it if
+// it was not.
+func (f *tinyLFU[K]) admitDoor(h uint64) bool {
+	b1, b2 := h&f.dmask, (h>>32)&f.dmask
+	seen := f.door[b1/64]&(1<<(b1%64)) != 0 && f.door[b2/64]&(1<<(b2%64)) != 0
+	f.door[b1/64] |= 1 << (b1 % 64)
+	f.door[b2/64] |= 1 << (b2 % 64)
+	return seen
+}
+
+// estimate returns the recent access count of key.
+func (f *tinyLFU[K]) estimate(key K) int {
+	h := seededHash(f.seed, key)
+	count := sketchMax
+	for i := range f.rows {
+		count = min(count, int(f.rows[i][f.index(h, i)]))
+	}
+	b1, b2 := h&f.dmask, (h>>32)&f.dmask
+	if f.door[b1/64]&(1<<(b1%64)) != 0 && f.door[b2/64]&(1<<(b2%64)) != 0 {
+		count += 1
+	}
+	return count
+}
+
+// reset halves every counter and clears the doorkeeper.
+func (f *tinyLFU[K]) reset() {
+	for i := range f.rows {
+		for j := range f.rows[i] {
+			f.rows[i][j] >>= 1
+		}
+	}
+	clear(f.door)
+	f.added /= 2
+}
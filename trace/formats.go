@@ -0,0 +1,270 @@
+package trace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Detect guesses the format of a trace from one of its lines. Plain CSV
+// cannot be told apart from the other comma separated formats, so it has to
+// be asked for explicitly.
+func Detect(line string) (Format, error) {
+	if strings.Contains(line, ",") {
+		fields := splitTrim(line, ',')
+		switch {
+		case len(fields) == 5 && isInt(fields[0]) && isInt(fields[1]) && isOneOf(fields[3], "r", "w"):
+			return SPC, nil
+		case len(fields) == 7 && isInt(fields[0]) && isOneOf(fields[3], "read", "write"):
+			return MSR, nil
+		case len(fields) == 7 && isInt(fields[0]) && isOneOf(fields[5], twitterOps...):
+			return Twitter, nil
+		}
+		return Auto, fmt.Errorf("%w: cannot detect the format of %q; name it, or use csv with its columns", ErrFormat, line)
+	}
+
+	fields := strings.Fields(line)
+	switch {
+	case len(fields) == 4 && isInt(fields[0]) && isInt(fields[1]) && isInt(fields[3]):
+		return ARC, nil
+	case (len(fields) == 2 || len(fields) == 3) && isFloat(fields[0]):
+		return Webcachesim, nil
+	}
+	return Auto, fmt.Errorf("%w: cannot detect the format of %q", ErrFormat, line)
+}
+
+// parseWebcachesim reads "time id size". The size may be left out, in which
+// case the object is one byte.
+func parseWebcachesim(line string) (Request, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return Request{}, fmt.Errorf("%w: expected \"time id size\", got %q", ErrFormat, line)
+	}
+	req := Request{Key: fields[1], Size: 1}
+	var err error
+	if req.Time, err = parseTime(fields[0]); err != nil {
+		return Request{}, err
+	}
+	if len(fields) == 3 {
+		if req.Size, err = parseSize(fields[2]); err != nil {
+			return Request{}, err
+		}
+	}
+	return req, nil
+}
+
+// parseCSV reads a line with the given columns.
+func parseCSV(line string, cols Columns) (Request, error) {
+	fields := splitTrim(line, cols.Comma)
+	column := func(i int, name string) (string, error) {
+		if i >= len(fields) {
+			return "", fmt.Errorf("%w: no %s in column %d of %q", ErrFormat, name, i, line)
+		}
+		return fields[i], nil
+	}
+
+	req := Request{Size: 1}
+	var err error
+	if req.Key, err = column(cols.Key, "key"); err != nil {
+		return Request{}, err
+	}
+	if cols.Time >= 0 {
+		field, err := column(cols.Time, "time")
+		if err != nil {
+			return Request{}, err
+		}
+		if req.Time, err = parseTime(field); err != nil {
+			return Request{}, err
+		}
+	}
+	if cols.Size >= 0 {
+		field, err := column(cols.Size, "size")
+		if err != nil {
+			return Request{}, err
+		}
+		if req.Size, err = parseSize(field); err != nil {
+			return Request{}, err
+		}
+	}
+	return req, nil
+}
+
+// parseSPC reads "ASU,LBA,size,opcode,timestamp" where LBA counts 512 byte
+// sectors of the application storage unit ASU.
+func parseSPC(line string, blockSize int) ([]Request, error) {
+	fields := splitTrim(line, ',')
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected \"ASU,LBA,size,opcode,timestamp\", got %q", ErrFormat, line)
+	}
+	lba, err := parseInt(fields[1], "LBA")
+	if err != nil {
+		return nil, err
+	}
+	size, err := parseSize(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	if !isOneOf(fields[3], "r", "w") {
+		return nil, fmt.Errorf("%w: bad opcode %q", ErrFormat, fields[3])
+	}
+	time, err := parseTime(fields[4])
+	if err != nil {
+		return nil, err
+	}
+	return blocks(fields[0], lba*512, size, blockSize, time)
+}
+
+// parseARC reads "block count ignored request" from the ARC paper's traces,
+// where the request covers count blocks starting at block.
+func parseARC(line string, blockSize int) ([]Request, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("%w: expected \"block count ignored request\", got %q", ErrFormat, line)
+	}
+	start, err := parseInt(fields[0], "block")
+	if err != nil {
+		return nil, err
+	}
+	count, err := parseInt(fields[1], "block count")
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 || count > MaxBlocks {
+		return nil, fmt.Errorf("%w: bad block count %q (at most %d per request)", ErrFormat, fields[1], MaxBlocks)
+	}
+	reqs := make([]Request, count)
+	for i := range reqs {
+		reqs[i] = Request{Key: strconv.FormatInt(start+int64(i), 10), Size: blockSize}
+	}
+	return reqs, nil
+}
+
+// parseMSR reads "timestamp,host,disk,type,offset,size,latency" where the
+// timestamp counts 100ns ticks, as Windows filetimes do.
+func parseMSR(line string, blockSize int) ([]Request, error) {
+	fields := splitTrim(line, ',')
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("%w: expected \"timestamp,host,disk,type,offset,size,latency\", got %q", ErrFormat, line)
+	}
+	ticks, err := parseInt(fields[0], "timestamp")
+	if err != nil {
+		return nil, err
+	}
+	if !isOneOf(fields[3], "read", "write") {
+		return nil, fmt.Errorf("%w: bad request type %q", ErrFormat, fields[3])
+	}
+	offset, err := parseInt(fields[4], "offset")
+	if err != nil {
+		return nil, err
+	}
+	size, err := parseSize(fields[5])
+	if err != nil {
+		return nil, err
+	}
+	return blocks(fields[1]+":"+fields[2], offset, size, blockSize, float64(ticks)/1e7)
+}
+
+// twitterOps are the operations in Twitter's cache traces.
+var twitterOps = []string{"get", "gets", "set", "add", "replace", "cas", "append", "prepend", "delete", "incr", "decr"}
+
+// parseTwitter reads "timestamp,key,key size,value size,client,op,ttl".
+// Every operation counts as a request for the key, of the key and value's
+// combined size.
+func parseTwitter(line string) (Request, error) {
+	fields := splitTrim(line, ',')
+	if len(fields) != 7 {
+		return Request{}, fmt.Errorf("%w: expected \"timestamp,key,key size,value size,client,op,ttl\", got %q", ErrFormat, line)
+	}
+	time, err := parseTime(fields[0])
+	if err != nil {
+		return Request{}, err
+	}
+	keySize, err := parseSize(fields[2])
+	if err != nil {
+		return Request{}, err
+	}
+	valueSize, err := parseSize(fields[3])
+	if err != nil {
+		return Request{}, err
+	}
+	if !isOneOf(fields[5], twitterOps...) {
+		return Request{}, fmt.Errorf("%w: bad operation %q", ErrFormat, fields[5])
+	}
+	return Request{Time: time, Key: fields[1], Size: keySize + valueSize}, nil
+}
+
+// MaxBlocks is the most blocks one request of a block trace may cover, so
+// a corrupt line cannot make the reader allocate without bound.
+const MaxBlocks = 1 << 16
+
+// blocks splits size bytes at offset on device into one request per block.
+func blocks(device string, offset int64, size int, blockSize int, time float64) ([]Request, error) {
+	first := offset / int64(blockSize)
+	last := first
+	if size > 0 {
+		last = (offset + int64(size) - 1) / int64(blockSize)
+	}
+	if last-first >= MaxBlocks {
+		return nil, fmt.Errorf("%w: request of %d bytes covers more than %d blocks", ErrFormat, size, MaxBlocks)
+	}
+	reqs := make([]Request, 0, last-first+1)
+	for b := first; b <= last; b++ {
+		reqs = append(reqs, Request{
+			Time: time,
+			Key:  device + ":" + strconv.FormatInt(b, 10),
+			Size: blockSize,
+		})
+	}
+	return reqs, nil
+}
+
+func splitTrim(line string, sep rune) []string {
+	fields := strings.Split(line, string(sep))
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+func parseTime(s string) (float64, error) {
+	t, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad time %q", ErrFormat, s)
+	}
+	return t, nil
+}
+
+func parseSize(s string) (int, error) {
+	size, err := strconv.Atoi(s)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%w: bad size %q", ErrFormat, s)
+	}
+	return size, nil
+}
+
+func parseInt(s string, name string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%w: bad %s %q", ErrFormat, name, s)
+	}
+	return v, nil
+}
+
+func isInt(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+func isFloat(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func isOneOf(s string, options ...string) bool {
+	for _, option := range options {
+		if strings.EqualFold(s, option) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+// Package trace reads cache request traces in the formats used by cache
+// research: webcachesim, CSV, the SPC and ARC paper block traces, MSR
+// Cambridge block traces and Twitter's cache traces. Traces are streamed one
+// request at a time and may be compressed with gzip or bzip2.
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// A Request is one lookup of an object in a trace.
+type Request struct {
+	Time float64 // Seconds since an arbitrary, format-specific epoch
+	Key  string
+	Size int // Bytes
+}
+
+// A Format is a trace file layout.
+type Format int
+
+const (
+	Auto        Format = iota // Detect the format from the first line
+	Webcachesim               // "time id size", whitespace separated
+	CSV                       // Comma separated, with configurable columns
+	SPC                       // "ASU,LBA,size,opcode,timestamp", as in the UMass OLTP traces
+	ARC                       // "block count ignored request", as in the ARC paper's traces
+	MSR                       // "timestamp,host,disk,type,offset,size,latency", MSR Cambridge
+	Twitter                   // "timestamp,key,key size,value size,client,op,ttl"
+)
+
+var formatNames = []string{"auto", "webcachesim", "csv", "spc", "arc", "msr", "twitter"}
+
+func (f Format) String() string {
+	if int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	for i, n := range formatNames {
+		if strings.EqualFold(name, n) {
+			return Format(i), nil
+		}
+	}
+	return Auto, fmt.Errorf("unknown trace format %q (available: %s)", name, strings.Join(formatNames, ", "))
+}
+
+// Columns says which zero-based column holds each field of a CSV trace. A
+// negative column means the field is absent: requests then have no time, or
+// a size of one byte. A CSV trace always needs a key column.
+type Columns struct {
+	Time   int
+	Key    int
+	Size   int
+	Comma  rune // Defaults to ','
+	Header bool // Whether the first line is a header to skip
+}
+
+// DefaultColumns reads CSV traces laid out like webcachesim traces.
+var DefaultColumns = Columns{Time: 0, Key: 1, Size: 2}
+
+// Options control how a trace is read.
+type Options struct {
+	Format  Format
+	Columns Columns // For CSV traces
+
+	// BlockSize splits requests in block traces (SPC and MSR) into one
+	// request per block, keyed by device and block number, as a page cache
+	// would see them. It defaults to 512 bytes for SPC and ARC and 4096 for
+	// MSR. The ARC paper's traces already count in blocks, so for them it is
+	// only the size given to each block.
+	BlockSize int
+}
+
+// A Reader returns the requests of a trace one at a time.
+type Reader struct {
+	scanner *bufio.Scanner
+	format  Format
+	opts    Options
+	line    int
+	pending []Request // Blocks of the last block request still to return
+	first   *string   // A line read ahead for detection
+	header  bool      // Whether a CSV header still has to be skipped
+	closer  io.Closer
+}
+
+// Stdin is the path Open reads standard input for.
+const Stdin = "-"
+
+// Open returns a Reader for the trace file at path, or for standard input if
+// path is Stdin. Traces compressed with gzip or bzip2 are decompressed as
+// they are read, so memory use does not grow with the length of the trace.
+// Close the Reader when done.
+func Open(path string, opts Options) (*Reader, error) {
+	var f *os.File
+	if path == Stdin {
+		f = os.Stdin
+	} else {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := decompress(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	tr, err := NewReader(r, opts)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	tr.closer = f
+	return tr, nil
+}
+
+// decompress returns a reader of the decompressed contents of r if it starts
+// like a gzip or bzip2 stream, or of r as it is otherwise.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(3)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bzip2.NewReader(br), nil
+	}
+	return br, nil
+}
+
+// Close closes the file opened by Open.
+func (tr *Reader) Close() error {
+	if tr.closer == nil {
+		return nil
+	}
+	return tr.closer.Close()
+}
+
+// ErrFormat is wrapped by every error about a malformed trace line.
+var ErrFormat = errors.New("malformed trace")
+
+// NewReader returns a Reader for r. With the Auto format, the format is
+// detected from the first line that is not blank or a comment.
+func NewReader(r io.Reader, opts Options) (*Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	tr := &Reader{scanner: scanner, format: opts.Format, opts: opts}
+	if tr.format == Auto {
+		line, ok := tr.next()
+		if !ok {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			// An empty trace has no requests in any format
+			tr.format = Webcachesim
+			return tr, nil
+		}
+		format, err := Detect(line)
+		if err != nil {
+			return nil, fmt.Errorf("trace: line %d: %w", tr.line, err)
+		}
+		tr.format = format
+		tr.first = &line
+	}
+	if tr.format == CSV && tr.opts.Columns.Key < 0 {
+		return nil, errors.New("trace: CSV traces need a key column")
+	}
+	if tr.opts.Columns.Comma == 0 {
+		tr.opts.Columns.Comma = ','
+	}
+	if tr.opts.BlockSize <= 0 {
+		switch tr.format {
+		case SPC, ARC:
+			tr.opts.BlockSize = 512
+		case MSR:
+			tr.opts.BlockSize = 4096
+		}
+	}
+	tr.header = tr.format == CSV && tr.opts.Columns.Header
+	return tr, nil
+}
+
+// Format returns the format being read, which is never Auto.
+func (tr *Reader) Format() Format {
+	return tr.format
+}
+
+// Timed reports whether the requests read have times: the ARC paper's
+// traces and CSV traces without a time column have none, and their
+// requests all have a Time of 0.
+func (tr *Reader) Timed() bool {
+	return tr.format != ARC && !(tr.format == CSV && tr.opts.Columns.Time < 0)
+}
+
+// Read returns the next request, or io.EOF at the end of the trace. Errors
+// about malformed lines wrap ErrFormat and name the line.
+func (tr *Reader) Read() (Request, error) {
+	for {
+		if len(tr.pending) > 0 {
+			req := tr.pending[0]
+			tr.pending = tr.pending[1:]
+			return req, nil
+		}
+
+		var line string
+		if tr.first != nil {
+			line, tr.first = *tr.first, nil
+		} else {
+			var ok bool
+			if line, ok = tr.next(); !ok {
+				if err := tr.scanner.Err(); err != nil {
+					return Request{}, err
+				}
+				return Request{}, io.EOF
+			}
+		}
+		if tr.header {
+			tr.header = false
+			continue
+		}
+
+		reqs, err := tr.parse(line)
+		if err != nil {
+			return Request{}, fmt.Errorf("trace: line %d: %s: %w", tr.line, tr.format, err)
+		}
+		tr.pending = reqs
+	}
+}
+
+// next returns the next line that is not blank or a comment.
+func (tr *Reader) next() (string, bool) {
+	for tr.scanner.Scan() {
+		tr.line++
+		line := strings.TrimSpace(tr.scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		return line, true
+	}
+	return "", false
+}
+
+// parse turns a line into its requests: one for an object trace, or one per
+// block for a block trace.
+func (tr *Reader) parse(line string) ([]Request, error) {
+	switch tr.format {
+	case Webcachesim:
+		req, err := parseWebcachesim(line)
+		return []Request{req}, err
+	case CSV:
+		req, err := parseCSV(line, tr.opts.Columns)
+		return []Request{req}, err
+	case SPC:
+		return parseSPC(line, tr.opts.BlockSize)
+	case ARC:
+		return parseARC(line, tr.opts.BlockSize)
+	case MSR:
+		return parseMSR(line, tr.opts.BlockSize)
+	case Twitter:
+		req, err := parseTwitter(line)
+		return []Request{req}, err
+	}
+	return nil, fmt.Errorf("unsupported format")
+}
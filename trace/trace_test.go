@@ -0,0 +1,193 @@
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readAll returns every request of a trace given as text.
+func readAll(t *testing.T, text string, opts Options) ([]Request, Format) {
+	tr, err := NewReader(strings.NewReader(text), opts)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	var reqs []Request
+	for {
+		req, err := tr.Read()
+		if err == io.EOF {
+			return reqs, tr.Format()
+		}
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+// Checks that every format is detected and read
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format Format
+		want   []Request
+	}{
+		{
+			"webcachesim",
+			"# comment\n78 334 1\n\n197 56 20\n263 65\n",
+			Webcachesim,
+			[]Request{{78, "334", 1}, {197, "56", 20}, {263, "65", 1}},
+		},
+		{
+			"spc",
+			"0,20941264,8192,W,0.551706\n1,100,512,r,1.5\n",
+			SPC,
+			[]Request{
+				{0.551706, "0:20941264", 512}, {0.551706, "0:20941265", 512},
+				{0.551706, "0:20941266", 512}, {0.551706, "0:20941267", 512},
+				{0.551706, "0:20941268", 512}, {0.551706, "0:20941269", 512},
+				{0.551706, "0:20941270", 512}, {0.551706, "0:20941271", 512},
+				{0.551706, "0:20941272", 512}, {0.551706, "0:20941273", 512},
+				{0.551706, "0:20941274", 512}, {0.551706, "0:20941275", 512},
+				{0.551706, "0:20941276", 512}, {0.551706, "0:20941277", 512},
+				{0.551706, "0:20941278", 512}, {0.551706, "0:20941279", 512},
+				{1.5, "1:100", 512},
+			},
+		},
+		{
+			"arc",
+			"1000 2 0 0\n7 1 0 1\n",
+			ARC,
+			[]Request{{0, "1000", 512}, {0, "1001", 512}, {0, "7", 512}},
+		},
+		{
+			"msr",
+			"128166372003061629,hm,1,Read,8192,6144,1331\n",
+			MSR,
+			[]Request{{12816637200.3061629, "hm:1:2", 4096}, {12816637200.3061629, "hm:1:3", 4096}},
+		},
+		{
+			"twitter",
+			"0,key-a,10,90,1,get,0\n1,key-b,5,0,2,delete,0\n",
+			Twitter,
+			[]Request{{0, "key-a", 100}, {1, "key-b", 5}},
+		},
+	}
+	for _, test := range tests {
+		got, format := readAll(t, test.text, Options{})
+		if format != test.format {
+			t.Errorf("%s: detected format %s. Should be: %s", test.name, format, test.format)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: read %v. Should be: %v", test.name, got, test.want)
+		}
+	}
+}
+
+// Checks CSV traces with configured columns and a header
+func TestCSV(t *testing.T) {
+	text := "size;key;ignored\n10;a;x\n20;b;y\n"
+	opts := Options{Format: CSV, Columns: Columns{Time: -1, Key: 1, Size: 0, Comma: ';', Header: true}}
+
+	got, _ := readAll(t, text, opts)
+	want := []Request{{0, "a", 10}, {0, "b", 20}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read %v. Should be: %v", got, want)
+	}
+}
+
+// Checks that the block size can be overridden
+func TestBlockSize(t *testing.T) {
+	got, _ := readAll(t, "0,0,8192,R,0\n", Options{BlockSize: 4096})
+	if len(got) != 2 || got[1].Key != "0:1" || got[1].Size != 4096 {
+		t.Errorf("Read %v. Should be two 4096 byte blocks", got)
+	}
+}
+
+// Checks that malformed lines give errors naming the line
+func TestMalformed(t *testing.T) {
+	tests := []struct {
+		text string
+		opts Options
+		want string
+	}{
+		{"1 a 1\n2\n", Options{}, "line 2"},
+		{"1 a 1\n2 b big\n", Options{}, "bad size"},
+		{"0,1,512,X,0\n", Options{Format: SPC}, "bad opcode"},
+		{"a,b\n", Options{Format: CSV, Columns: Columns{Time: -1, Key: 1, Size: 2}}, "no size"},
+		{"0 99999999999 0 0\n", Options{Format: ARC}, "bad block count"},
+		{"0,0,99999999999,R,0\n", Options{Format: SPC}, "more than 65536 blocks"},
+		{"0,hm,1,Read,0,99999999999,0\n", Options{Format: MSR}, "more than 65536 blocks"},
+	}
+	for _, test := range tests {
+		tr, err := NewReader(strings.NewReader(test.text), test.opts)
+		for err == nil {
+			_, err = tr.Read()
+		}
+		if err == io.EOF || !errors.Is(err, ErrFormat) || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Reading %q gave error %v. Should mention: %s", test.text, err, test.want)
+		}
+	}
+
+	if _, err := NewReader(strings.NewReader("what is this\n"), Options{}); !errors.Is(err, ErrFormat) {
+		t.Errorf("Detecting an unknown format gave error %v. Should be: %v", err, ErrFormat)
+	}
+}
+
+// Checks that gzip and bzip2 traces are decompressed while read
+func TestDecompress(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("1 a 1\n2 b 2\n"))
+	w.Close()
+
+	// "1 a 1\n2 b 2\n" compressed with bzip2
+	bz, _ := hex.DecodeString("425a6839314159265359cf2a3d3c000003d9000010400030003000200030c0087a9e88b2f36819e2ee48a70a1219e547a780")
+
+	want := []Request{{1, "a", 1}, {2, "b", 2}}
+	for name, data := range map[string][]byte{"plain": []byte("1 a 1\n2 b 2\n"), "gzip": gz.Bytes(), "bzip2": bz} {
+		r, err := decompress(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: decompress failed: %v", name, err)
+			continue
+		}
+		text, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: read failed: %v", name, err)
+			continue
+		}
+		if got, _ := readAll(t, string(text), Options{}); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: read %v. Should be: %v", name, got, want)
+		}
+	}
+}
+
+// Checks that only formats whose requests have times are timed
+func TestTimed(t *testing.T) {
+	tests := []struct {
+		text string
+		opts Options
+		want bool
+	}{
+		{"78 334 1\n", Options{}, true},
+		{"1000 2 0 0\n", Options{}, false},
+		{"a,1\n", Options{Format: CSV, Columns: Columns{Time: -1, Key: 0, Size: 1}}, false},
+		{"0,a,1\n", Options{Format: CSV, Columns: DefaultColumns}, true},
+	}
+	for _, test := range tests {
+		tr, err := NewReader(strings.NewReader(test.text), test.opts)
+		if err != nil {
+			t.Fatalf("NewReader failed: %v", err)
+		}
+		if got := tr.Timed(); got != test.want {
+			t.Errorf("%s trace %q: Timed is %v. Should be: %v", tr.Format(), test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package cache
+
+// A SimpleTwoQ is the simplified 2Q of Johnson and Shasha (VLDB 1994). New
+// pages enter A1, a FIFO, and only move to the LRU Am when they are used
+// again while in A1, so pages used once do not push out pages used often.
+type SimpleTwoQ[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	kin            int // Pages A1 may hold before it is evicted from first
+
+	a1 *LRU[K, V] // FIFO of pages used once
+	am *LRU[K, V] // LRU of pages used more than once
+
+	hits   int
+	misses int
+}
+
+// NewSimpleTwoQ returns a simplified 2Q holding pages bindings of up to
+// limit/pages bytes, evicting from A1 while it holds more than kin pages.
+// The paper suggests kin of about a quarter of the pages.
+func NewSimpleTwoQ[K comparable, V any](limit int, pages int, kin int) *SimpleTwoQ[K, V] {
+	return &SimpleTwoQ[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		kin:            min(max(kin, 0), pages),
+		a1:             NewLru[K, V](limit, pages),
+		am:             NewLru[K, V](limit, pages),
+	}
+}
+
+func (q *SimpleTwoQ[K, V]) MaxPages() int {
+	return q.num_pages
+}
+
+func (q *SimpleTwoQ[K, V]) RemainingPages() int {
+	return q.num_pages - q.Len()
+}
+
+// Get returns the value of key, moving it to Am if it was in A1.
+func (q *SimpleTwoQ[K, V]) Get(key K) (value V, ok bool) {
+	if v, ok := q.am.Peek(key); ok {
+		q.am.Set(key, v)
+		q.hits += 1
+		return v, true
+	}
+	if v, ok := q.a1.Remove(key); ok {
+		q.am.Set(key, v)
+		q.hits += 1
+		return v, true
+	}
+	q.misses += 1
+	return value, false
+}
+
+// Set adds a new binding to A1. Setting a cached key counts as a use.
+func (q *SimpleTwoQ[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > q.bytes_per_page {
+		return false
+	}
+	if q.am.Contains(key) || q.a1.Contains(key) {
+		q.a1.Remove(key)
+		return q.am.Set(key, value)
+	}
+	if q.Len() == q.num_pages {
+		if q.a1.Len() > q.kin || q.am.Len() == 0 {
+			q.a1.RemoveLRU()
+		} else {
+			q.am.RemoveLRU()
+		}
+	}
+	return q.a1.Set(key, value)
+}
+
+func (q *SimpleTwoQ[K, V]) Len() int {
+	return q.a1.Len() + q.am.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (q *SimpleTwoQ[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   q.hits,
+		Misses: q.misses,
+	}
+}
+
+// A TwoQ is the full 2Q of Johnson and Shasha. New pages enter the FIFO
+// A1in, and hits there are treated as correlated references and ignored.
+// Pages evicted from A1in are remembered by key in the ghost FIFO A1out;
+// only a page requested again while in A1out is admitted to the LRU Am.
+type TwoQ[K comparable, V any] struct {
+	num_pages      int
+	bytes_per_page int
+	kin            int // Pages A1in may hold before it is evicted from first
+	kout           int // Keys remembered in A1out
+
+	a1in  *LRU[K, V]        // FIFO of new pages
+	a1out *LRU[K, struct{}] // FIFO of keys evicted from A1in
+	am    *LRU[K, V]        // LRU of pages requested again after leaving A1in
+
+	hits   int
+	misses int
+}
+
+// NewTwoQ returns a full 2Q holding pages bindings of up to limit/pages
+// bytes, with A1in holding kin pages and A1out remembering kout keys. The
+// paper suggests kin of a quarter and kout of half of the pages.
+func NewTwoQ[K comparable, V any](limit int, pages int, kin int, kout int) *TwoQ[K, V] {
+	kout = max(kout, 1)
+	return &TwoQ[K, V]{
+		num_pages:      pages,
+		bytes_per_page: limit / pages,
+		kin:            min(max(kin, 0), pages),
+		kout:           kout,
+		a1in:           NewLru[K, V](limit, pages),
+		a1out:          newGhost[K](kout),
+		am:             NewLru[K, V](limit, pages),
+	}
+}
+
+func (q *TwoQ[K, V]) MaxPages() int {
+	return q.num_pages
+}
+
+func (q *TwoQ[K, V]) RemainingPages() int {
+	return q.num_pages - q.Len()
+}
+
+// Get returns the value of key. A hit in Am makes it most recently used; a
+// hit in A1in changes nothing.
+func (q *TwoQ[K, V]) Get(key K) (value V, ok bool) {
+	if v, ok := q.am.Peek(key); ok {
+		q.am.Set(key, v)
+		q.hits += 1
+		return v, true
+	}
+	if v, ok := q.a1in.Peek(key); ok {
+		q.hits += 1
+		return v, true
+	}
+	q.misses += 1
+	return value, false
+}
+
+// Set adds a binding to Am if its key is remembered in A1out, or to A1in
+// otherwise. Setting a cached key replaces its value where it is.
+func (q *TwoQ[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > q.bytes_per_page {
+		return false
+	}
+	if q.am.Contains(key) {
+		return q.am.Set(key, value)
+	}
+	if q.a1in.Contains(key) {
+		q.a1in.update(key, value)
+		return true
+	}
+	if q.Len() == q.num_pages {
+		q.reclaim()
+	}
+	if q.a1out.Contains(key) {
+		q.a1out.Remove(key)
+		return q.am.Set(key, value)
+	}
+	return q.a1in.Set(key, value)
+}
+
+// reclaim frees a page, from A1in while it is over kin and from Am otherwise.
+func (q *TwoQ[K, V]) reclaim() {
+	if q.a1in.Len() > q.kin || q.am.Len() == 0 {
+		key, _ := q.a1in.RemoveLRU()
+		q.a1out.Set(key, struct{}{})
+		return
+	}
+	q.am.RemoveLRU()
+}
+
+func (q *TwoQ[K, V]) Len() int {
+	return q.a1in.Len() + q.am.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (q *TwoQ[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   q.hits,
+		Misses: q.misses,
+	}
+}
@@ -0,0 +1,122 @@
+/******************************************************************************
+ * twoq_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for twoq.go, following the behaviours described
+ *    in the 2Q paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that a page hit once in A1 of the simplified 2Q moves to Am and
+// outlives the pages behind it
+func TestSimpleTwoQPromotion(t *testing.T) {
+	q := NewSimpleTwoQ[string, []byte](limit, pages, 2)
+
+	addCache(q, 1, 8)
+	q.Get("key1")
+	if !q.am.Contains("key1") || q.a1.Contains("key1") {
+		t.Errorf("Failed to move a page hit in A1 to Am. Key is: %s", "key1")
+		t.FailNow()
+	}
+
+	addCache(q, 9, 20)
+	if _, ok := q.Get("key1"); !ok {
+		t.Errorf("Evicted a page from Am while A1 was over Kin. Key is: %s", "key1")
+		t.FailNow()
+	}
+	if q.Len() != pages {
+		t.Errorf("Failed to keep the cache full. Length is: %d when it should be %d", q.Len(), pages)
+		t.FailNow()
+	}
+}
+
+// Checks that hits in A1in are treated as correlated and that only a page
+// remembered in A1out is admitted to Am
+func TestTwoQCorrelatedReferences(t *testing.T) {
+	q := NewTwoQ[string, []byte](limit, pages, 2, 4)
+
+	addCache(q, 1, 8)
+	for i := 0; i < 3; i++ {
+		q.Get("key1")
+	}
+	if q.am.Contains("key1") {
+		t.Errorf("Moved a page with only correlated references to Am. Key is: %s", "key1")
+		t.FailNow()
+	}
+
+	addCache(q, 9, 9)
+	if q.a1in.Contains("key1") || !q.a1out.Contains("key1") {
+		t.Errorf("Failed to remember the page evicted from A1in in A1out. Key is: %s", "key1")
+		t.FailNow()
+	}
+
+	addCache(q, 1, 1)
+	if !q.am.Contains("key1") || q.a1out.Contains("key1") {
+		t.Errorf("Failed to admit a page remembered in A1out to Am. Key is: %s", "key1")
+		t.FailNow()
+	}
+}
+
+// Checks that a long scan of new pages does not flush pages in Am
+func TestTwoQScanResistance(t *testing.T) {
+	q := NewTwoQ[string, []byte](limit, pages, 2, pages)
+
+	// Request key1-4 again after they have left A1in
+	for i := 1; i <= 4; i++ {
+		addCache(q, i, i)
+		addCache(q, 100*i, 100*i+pages-1)
+		addCache(q, i, i)
+	}
+
+	// Scan pages that are never seen again
+	addCache(q, 1000, 1100)
+
+	for i := 1; i <= 4; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := q.Get(key); !ok {
+			t.Errorf("Scan flushed a page from Am. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks the size bounds of both queues on a random workload
+func TestTwoQBounds(t *testing.T) {
+	q := NewTwoQ[int, int](1024, 16, 4, 8)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := q.Get(key); !ok {
+			q.Set(key, key)
+		}
+
+		if q.Len() > 16 || q.a1out.Len() > 8 {
+			t.Errorf("Broke the size bounds. A1in %d, A1out %d, Am %d", q.a1in.Len(), q.a1out.Len(), q.am.Len())
+			t.FailNow()
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkTwoQ(b *testing.B) {
+	benchHits(b, NewTwoQ[int, int](1024, 64, 16, 32))
+}
@@ -0,0 +1,129 @@
+package cache
+
+// A WTinyLFU is the Window-TinyLFU policy of Einziger et al., as used by
+// Caffeine and Ristretto. New pages enter a small LRU window. A page
+// leaving the window is a candidate for the main space, a segmented LRU
+// of a probation and a protected segment, and is only admitted in place
+// of the probation segment's LRU page if TinyLFU estimates it has been
+// accessed more often recently. Pages hit in probation move to protected.
+type WTinyLFU[K comparable, V any] struct {
+	num_pages       int
+	bytes_per_page  int
+	window_pages    int // Pages held by the window
+	protected_pages int // Pages of the main space held by protected
+
+	window    *LRU[K, V]
+	probation *LRU[K, V]
+	protected *LRU[K, V]
+	filter    *tinyLFU[K]
+
+	hits   int
+	misses int
+}
+
+// NewWTinyLFU returns a W-TinyLFU holding pages bindings of up to
+// limit/pages bytes, window of which are in the window and protected of
+// the rest in the protected segment, and hashing keys into its sketch with
+// the given seed. Caffeine starts with a window of 1% of the pages and 80%
+// of the main space protected.
+func NewWTinyLFU[K comparable, V any](limit int, pages int, window int, protected int, seed int64) *WTinyLFU[K, V] {
+	window = min(max(window, 1), pages-1)
+	return &WTinyLFU[K, V]{
+		num_pages:       pages,
+		bytes_per_page:  limit / pages,
+		window_pages:    window,
+		protected_pages: min(max(protected, 0), pages-window),
+		window:          NewLru[K, V](limit, pages),
+		probation:       NewLru[K, V](limit, pages),
+		protected:       NewLru[K, V](limit, pages),
+		filter:          newTinyLFU[K](pages, seed),
+	}
+}
+
+func (w *WTinyLFU[K, V]) MaxPages() int {
+	return w.num_pages
+}
+
+func (w *WTinyLFU[K, V]) RemainingPages() int {
+	return w.num_pages - w.Len()
+}
+
+// Get counts an access to key in the frequency sketch and returns its value,
+// making it most recently used in its segment or promoting it from
+// probation to protected.
+func (w *WTinyLFU[K, V]) Get(key K) (value V, ok bool) {
+	w.filter.increment(key)
+	if v, ok := w.window.Peek(key); ok {
+		w.window.Set(key, v)
+		w.hits += 1
+		return v, true
+	}
+	if v, ok := w.protected.Peek(key); ok {
+		w.protected.Set(key, v)
+		w.hits += 1
+		return v, true
+	}
+	if v, ok := w.probation.Remove(key); ok {
+		w.protected.Set(key, v)
+		if w.protected.Len() > w.protected_pages {
+			k, v := w.protected.RemoveLRU()
+			w.probation.Set(k, v)
+		}
+		w.hits += 1
+		return v, true
+	}
+	w.misses += 1
+	return value, false
+}
+
+// Set adds the binding to the window, offering the window's LRU page to the
+// main space if the window is full. Setting a cached key replaces its value
+// where it is. Accesses are only counted by Get.
+func (w *WTinyLFU[K, V]) Set(key K, value V) bool {
+	if DefaultSize(key, value) > w.bytes_per_page {
+		return false
+	}
+	for _, segment := range []*LRU[K, V]{w.window, w.probation, w.protected} {
+		if segment.Contains(key) {
+			segment.update(key, value)
+			return true
+		}
+	}
+
+	w.window.Set(key, value)
+	if w.window.Len() > w.window_pages {
+		w.admit(w.window.RemoveLRU())
+	}
+	return true
+}
+
+// admit adds a candidate from the window to probation if the main space
+// has room, or if TinyLFU estimates it is more popular than the page the
+// main space would evict, which is then evicted instead.
+func (w *WTinyLFU[K, V]) admit(key K, value V) {
+	if w.probation.Len()+w.protected.Len() < w.num_pages-w.window_pages {
+		w.probation.Set(key, value)
+		return
+	}
+	segment := w.probation
+	if segment.Len() == 0 {
+		segment = w.protected
+	}
+	victim, _ := segment.oldest()
+	if w.filter.estimate(key) > w.filter.estimate(victim) {
+		segment.Remove(victim)
+		w.probation.Set(key, value)
+	}
+}
+
+func (w *WTinyLFU[K, V]) Len() int {
+	return w.window.Len() + w.probation.Len() + w.protected.Len()
+}
+
+// Stats returns statistics about how many search hits and misses have occurred.
+func (w *WTinyLFU[K, V]) Stats() *Stats {
+	return &Stats{
+		Hits:   w.hits,
+		Misses: w.misses,
+	}
+}
@@ -0,0 +1,207 @@
+/******************************************************************************
+ * wtinylfu_test.go
+ * Author:
+ * Usage:    `go test`  or  `go test -v`
+ * Description:
+ *    An unit testing suite for tinylfu.go and wtinylfu.go, following the
+ *    behaviours described in the TinyLFU paper.
+ ******************************************************************************/
+
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// Looks up keys start to end, setting the ones that miss
+func requestKeys(c Cache[string, []byte], start int, end int) {
+	for i := start; i <= end; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, []byte(key))
+		}
+	}
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// Checks that the doorkeeper absorbs the first access and the sketch counts
+// the rest, up to its four bit counters
+func TestTinyLFUEstimate(t *testing.T) {
+	f := newTinyLFU[string](64, 1)
+
+	if n := f.estimate("a"); n != 0 {
+		t.Errorf("Estimated an unseen key. Estimate is: %d", n)
+		t.FailNow()
+	}
+	f.increment("a")
+	if n := f.estimate("a"); n != 1 {
+		t.Errorf("Failed to count the first access in the doorkeeper. Estimate is: %d", n)
+		t.FailNow()
+	}
+	for i := 0; i < 4; i++ {
+		f.increment("a")
+	}
+	if n := f.estimate("a"); n != 5 {
+		t.Errorf("Failed to count accesses. Estimate is: %d when it should be %d", n, 5)
+		t.FailNow()
+	}
+	for i := 0; i < 40; i++ {
+		f.increment("b")
+	}
+	if n := f.estimate("b"); n != sketchMax+1 {
+		t.Errorf("Failed to saturate a counter. Estimate is: %d when it should be %d", n, sketchMax+1)
+		t.FailNow()
+	}
+}
+
+// Checks that counters are halved and the doorkeeper cleared after a sample
+func TestTinyLFUAging(t *testing.T) {
+	f := newTinyLFU[string](64, 1)
+
+	for i := 0; i < 9; i++ {
+		f.increment("a")
+	}
+	// Fill the rest of the sample with keys seen once
+	for i := 9; i < f.sample; i++ {
+		f.increment(fmt.Sprintf("key%d", i))
+	}
+	if f.added != f.sample/2 {
+		t.Errorf("Failed to reset after a sample. Accesses counted: %d", f.added)
+		t.FailNow()
+	}
+	if n := f.estimate("a"); n != 4 {
+		t.Errorf("Failed to halve the counters. Estimate is: %d when it should be %d", n, 4)
+		t.FailNow()
+	}
+}
+
+// Checks that a page leaving the window only replaces a main page if it
+// has been accessed more often
+func TestWTinyLFUAdmission(t *testing.T) {
+	w := NewWTinyLFU[string, []byte](limit, pages, 1, 5, 1)
+
+	requestKeys(w, 1, 8)
+	for round := 0; round < 3; round++ {
+		requestKeys(w, 1, 7)
+	}
+
+	// key8 leaves the window, but was only seen once
+	requestKeys(w, 100, 100)
+	if w.probation.Contains("key8") || w.protected.Contains("key8") {
+		t.Errorf("Admitted a page less popular than the main space's victim. Key is: %s", "key8")
+		t.FailNow()
+	}
+
+	// key300 has missed often, so it is admitted when it leaves the window
+	for i := 0; i < 10; i++ {
+		w.Get("key300")
+	}
+	requestKeys(w, 300, 300)
+	requestKeys(w, 101, 101)
+	if !w.probation.Contains("key300") {
+		t.Errorf("Failed to admit a popular page to the main space. Key is: %s", "key300")
+		t.FailNow()
+	}
+	if w.Len() != pages {
+		t.Errorf("Failed to keep the cache full. Length is: %d when it should be %d", w.Len(), pages)
+		t.FailNow()
+	}
+}
+
+// Checks that a page hit in probation moves to protected, demoting the
+// protected segment's LRU page when it is full
+func TestWTinyLFUPromotion(t *testing.T) {
+	w := NewWTinyLFU[string, []byte](limit, pages, 1, 2, 1)
+
+	requestKeys(w, 1, 8)
+	requestKeys(w, 1, 3)
+	if !w.protected.Contains("key2") || !w.protected.Contains("key3") {
+		t.Errorf("Failed to promote pages hit in probation. Protected length is: %d", w.protected.Len())
+		t.FailNow()
+	}
+	if !w.probation.Contains("key1") {
+		t.Errorf("Failed to demote the LRU page of protected. Key is: %s", "key1")
+		t.FailNow()
+	}
+}
+
+// Checks that a scan of new pages does not flush popular pages
+func TestWTinyLFUScanResistance(t *testing.T) {
+	w := NewWTinyLFU[string, []byte](limit, pages, 1, 5, 1)
+
+	for round := 0; round < 4; round++ {
+		requestKeys(w, 1, 4)
+	}
+	// Stay within one sample, so the counts are not aged
+	requestKeys(w, 1000, 1050)
+
+	for i := 1; i <= 4; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if _, ok := w.Get(key); !ok {
+			t.Errorf("Scan flushed a popular page. Key is: %s", key)
+			t.FailNow()
+		}
+	}
+}
+
+// Checks the size bounds of the segments on a random workload
+func TestWTinyLFUBounds(t *testing.T) {
+	w := NewWTinyLFU[int, int](1024, 16, 2, 11, 1)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(48)
+		if i%3 == 0 {
+			key = 1000 + i // Some one-off pages
+		}
+		if _, ok := w.Get(key); !ok {
+			w.Set(key, key)
+		}
+
+		if w.Len() > 16 || w.window.Len() > 2 || w.protected.Len() > 11 {
+			t.Errorf("Broke the size bounds. Window %d, probation %d, protected %d", w.window.Len(), w.probation.Len(), w.protected.Len())
+			t.FailNow()
+		}
+	}
+}
+
+// Checks that the same seed gives the same results in every run
+func TestWTinyLFUSeed(t *testing.T) {
+	keys := readKeys(t, "trace1.txt")
+	replay := func(seed int64) *Stats {
+		w := NewWTinyLFU[string, int](1000, 100, 1, 80, seed)
+		for _, key := range keys {
+			if _, ok := w.Get(key); !ok {
+				w.Set(key, 0)
+			}
+		}
+		return w.Stats()
+	}
+
+	first := replay(1)
+	if again := replay(1); !first.Equals(again) {
+		t.Errorf("Changed the results with the same seed. Hits are: %d when they should be %d", again.Hits, first.Hits)
+		t.FailNow()
+	}
+	if other := replay(2); first.Equals(other) {
+		t.Errorf("Failed to change the sketch with another seed. Hits are: %d", other.Hits)
+		t.FailNow()
+	}
+}
+
+/******************************************************************************/
+/*                                Benchmarks                                  */
+/******************************************************************************/
+
+func BenchmarkWTinyLFU(b *testing.B) {
+	benchHits(b, NewWTinyLFU[int, int](1024, 64, 1, 50, 1))
+}